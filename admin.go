@@ -0,0 +1,42 @@
+package clog
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// RequireToken wraps an admin handler (such as DumpGoroutinesHandler) so
+// that it only serves requests carrying the token in an
+// "Authorization: Bearer <token>" header. Other requests get a 401
+// Unauthorized response. If the token is empty (eg. taken from an unset
+// environment variable), every request is denied. Any other
+// authentication can be added by wrapping the handlers in the usual http
+// middleware.
+func RequireToken(token string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if token == "" || !strings.HasPrefix(auth, "Bearer ") ||
+			subtle.ConstantTimeCompare([]byte(auth[len("Bearer "):]), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// ReadOnly wraps an admin handler so that it only serves GET and HEAD
+// requests. Requests which could change the logger state or trigger an
+// action get a 403 Forbidden response.
+func ReadOnly(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "read-only", http.StatusForbidden)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
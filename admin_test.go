@@ -0,0 +1,66 @@
+package clog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireToken(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := RequireToken("s3cret", ok)
+
+	for auth, code := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"s3cret":        http.StatusUnauthorized,
+		"Bearer s3cret": http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != code {
+			t.Errorf("Expected status %d for %q, got %d", code, auth, rec.Code)
+		}
+	}
+}
+
+func TestRequireTokenEmpty(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := RequireToken("", ok)
+
+	for _, auth := range []string{"", "Bearer ", "Bearer x"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for %q with an empty token, got %d", auth, rec.Code)
+		}
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := ReadOnly(ok)
+
+	for method, code := range map[string]int{
+		http.MethodGet:  http.StatusOK,
+		http.MethodHead: http.StatusOK,
+		http.MethodPost: http.StatusForbidden,
+		http.MethodPut:  http.StatusForbidden,
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/", nil))
+		if rec.Code != code {
+			t.Errorf("Expected status %d for %s, got %d", code, method, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package clog
+
+import "fmt"
+
+// SetAudit enables logging a message with INFO level whenever the logger
+// configuration (level, color, output or timestamp mode) is changed,
+// describing which function changed it and the old and new values. Audit
+// messages are logged regardless of the log level, so raising the level
+// is traced as well.
+func SetAudit(enabled bool) {
+	std.SetAudit(enabled)
+}
+
+// SetAudit enables logging a message with INFO level whenever the logger
+// configuration is changed.
+func (l *Logger) SetAudit(enabled bool) {
+	l.mu.Lock()
+	l.audit = enabled
+	l.mu.Unlock()
+}
+
+// auditChange logs the configuration change if auditing is enabled. The
+// output is always reported as changed, as writers can't be compared. The
+// message bypasses the level filter, as the new level may be above INFO.
+func (l *Logger) auditChange(source, setting, old, new string) {
+	l.mu.Lock()
+	enabled := l.audit
+	now := l.clockLocked().Now()
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	if !enabled || (old == new && setting != "output") {
+		return
+	}
+
+	msg := fmt.Sprintf("config changed by %s: %s %s -> %s", source, setting, old, new)
+	var fields Fields
+	if l.fireHooks(hooks, "", INFO, now, &msg, &fields) {
+		l.write("", INFO, now, msg, fields)
+	}
+}
@@ -0,0 +1,53 @@
+package clog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+	SetAudit(true)
+	defer SetAudit(false)
+
+	SetOutput(&out)
+	if !strings.Contains(out.String(), " INFO config changed by SetOutput: output *bytes.Buffer -> *bytes.Buffer") {
+		t.Errorf("Output change not audited: %s", out.String())
+	}
+
+	out.Reset()
+	SetTimestampMode(TimestampRFC3339)
+	if out.Len() != 0 {
+		t.Errorf("Unchanged setting audited: %s", out.String())
+	}
+
+	SetTimestampMode(TimestampEpoch)
+	SetTimestampMode(TimestampRFC3339)
+	if !strings.Contains(out.String(), " INFO config changed by SetTimestampMode: timestamp 2 -> 0") {
+		t.Errorf("Timestamp mode change not audited: %s", out.String())
+	}
+}
+
+func TestAuditRaiseLevel(t *testing.T) {
+	out := bytes.Buffer{}
+
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetAudit(true)
+
+	l.SetLevel(ERROR)
+	if !strings.Contains(out.String(), " INFO config changed by SetLevel: level DEBUG -> ERROR") {
+		t.Errorf("Level raise not audited: %q", out.String())
+	}
+
+	l.SetLevel(PANIC)
+	out.Reset()
+	l.SetOutput(&out)
+	if !strings.Contains(out.String(), "config changed by SetOutput") {
+		t.Errorf("Output change above the level not audited: %q", out.String())
+	}
+}
@@ -0,0 +1,87 @@
+package clog
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// pkgPrefix is the prefix of the names of functions in this package, used
+// to skip over them when looking for the caller.
+var pkgPrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndex(name, "/")
+	return name[:slash+1+strings.Index(name[slash+1:], ".")+1]
+}()
+
+// compatPrefix is the prefix of the compatibility packages under compat/,
+// which are also skipped when looking for the caller.
+var compatPrefix = strings.TrimSuffix(pkgPrefix, ".") + "/compat/"
+
+// slogPrefix and stdlogPrefix are the prefixes of the log/slog and log
+// functions, skipped when logging through NewSlogHandler() or StdLogger().
+const (
+	slogPrefix   = "log/slog."
+	stdlogPrefix = "log."
+)
+
+// SetCaller enables showing the file and line of the code that logged the
+// message, and optionally the function name.
+func SetCaller(enabled bool, showFunc bool) {
+	std.SetCaller(enabled, showFunc)
+}
+
+// SetCallerSkip sets the number of additional stack frames to skip when
+// looking for the caller. Calls within this package are always skipped;
+// the skip is needed when logging through wrapper functions, so that the
+// caller of the wrapper is reported.
+func SetCallerSkip(skip int) {
+	std.SetCallerSkip(skip)
+}
+
+// SetCaller enables showing the file and line of the code that logged the
+// message, and optionally the function name.
+func (l *Logger) SetCaller(enabled bool, showFunc bool) {
+	l.mu.Lock()
+	l.caller = enabled
+	l.callerFunc = showFunc
+	l.mu.Unlock()
+}
+
+// SetCallerSkip sets the number of additional stack frames to skip when
+// looking for the caller.
+func (l *Logger) SetCallerSkip(skip int) {
+	l.mu.Lock()
+	l.callerSkip = skip
+	l.mu.Unlock()
+}
+
+// findCaller returns the location of the first stack frame outside this
+// package, the compatibility packages, log and log/slog (not counting
+// their tests), after skipping skip more frames, as "dir/file.go:line" and
+// the function name.
+func findCaller(skip int) (string, string) {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	for {
+		f, more := frames.Next()
+		internal := (strings.HasPrefix(f.Function, pkgPrefix) || strings.HasPrefix(f.Function, compatPrefix) ||
+			strings.HasPrefix(f.Function, slogPrefix) || strings.HasPrefix(f.Function, stdlogPrefix)) &&
+			!strings.HasSuffix(f.File, "_test.go")
+
+		if !internal {
+			if skip == 0 {
+				file := filepath.Join(filepath.Base(filepath.Dir(f.File)), filepath.Base(f.File))
+				return filepath.ToSlash(file) + ":" + strconv.Itoa(f.Line), f.Function
+			}
+			skip--
+		}
+
+		if !more {
+			return "???", ""
+		}
+	}
+}
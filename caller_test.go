@@ -0,0 +1,62 @@
+package clog
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func logThroughWrapper(msg string) {
+	Warning(msg)
+}
+
+// prevLine returns "/<file>:<line>" for the line before the one it's
+// called from.
+func prevLine() string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("/%s:%d", filepath.Base(file), line-1)
+}
+
+func TestCaller(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+	SetCaller(true, false)
+	defer SetCaller(false, false)
+
+	Info("direct")
+	if loc := prevLine(); !strings.HasSuffix(out.String(), loc+" direct\n") {
+		t.Errorf("Incorrect caller, expected %s: %s", loc, out.String())
+	}
+
+	out.Reset()
+	WithFields(Fields{"a": 1}).Infof("entry %d", 1)
+	if loc := prevLine(); !strings.HasSuffix(out.String(), loc+" entry 1 a=1\n") {
+		t.Errorf("Incorrect caller through entry, expected %s: %s", loc, out.String())
+	}
+
+	out.Reset()
+	SetCallerSkip(1)
+	defer SetCallerSkip(0)
+	SetCaller(true, true)
+
+	logThroughWrapper("wrapped")
+	if loc := prevLine(); !strings.HasSuffix(out.String(), loc+":"+pkgPrefix+"TestCaller wrapped\n") {
+		t.Errorf("Incorrect caller through wrapper, expected %s: %s", loc, out.String())
+	}
+
+	out.Reset()
+	SetCallerSkip(0)
+	SetCaller(true, false)
+	SetFormat(FormatJSON)
+	defer SetFormat(FormatText)
+
+	Info("json")
+	if loc := prevLine(); !strings.Contains(out.String(), `,"caller":"`) || !strings.Contains(out.String(), loc+`"}`) {
+		t.Errorf("Incorrect caller in JSON output, expected %s: %s", loc, out.String())
+	}
+}
@@ -0,0 +1,111 @@
+package clog
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+)
+
+// CardinalityAction specifies what happens to a field value over the limit
+// set with LimitFieldCardinality().
+type CardinalityAction int
+
+// Available cardinality actions
+const (
+	// CardinalityDrop removes the field from the message.
+	CardinalityDrop CardinalityAction = iota
+	// CardinalityHash replaces the value with one of limit buckets
+	// ("bucket-0", "bucket-1", ...), chosen by hashing the value.
+	CardinalityHash
+)
+
+type cardinality struct {
+	limit    int
+	per      time.Duration
+	action   CardinalityAction
+	start    time.Time
+	seen     map[string]struct{}
+	reported bool
+}
+
+// LimitFieldCardinality limits the number of distinct values of the field
+// in messages logged within each period of the specified duration. Values
+// over the limit are dropped or hashed into buckets, depending on the
+// action, and the first one in each period is reported to the diagnostics
+// output. This protects log storage indexing the fields (eg. Loki or
+// Elasticsearch) from fields like user IDs. If per is 0 or negative, the
+// period never ends, limiting the distinct values for the lifetime of the
+// logger. If limit is 0, the field is no longer limited.
+func LimitFieldCardinality(key string, limit int, per time.Duration, action CardinalityAction) {
+	std.LimitFieldCardinality(key, limit, per, action)
+}
+
+// LimitFieldCardinality limits the number of distinct values of the field
+// in messages logged within each period of the specified duration.
+func (l *Logger) LimitFieldCardinality(key string, limit int, per time.Duration, action CardinalityAction) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit <= 0 {
+		delete(l.cardinality, key)
+		return
+	}
+
+	if l.cardinality == nil {
+		l.cardinality = map[string]*cardinality{}
+	}
+	l.cardinality[key] = &cardinality{limit: limit, per: per, action: action}
+}
+
+// limitCardinality returns the fields with the cardinality limits applied.
+// The fields are copied if they need to be changed. It must be called with
+// the lock held.
+func (l *Logger) limitCardinality(now time.Time, fields Fields) Fields {
+	copied := false
+
+	for key, c := range l.cardinality {
+		v, ok := fields[key]
+		if !ok {
+			continue
+		}
+
+		if c.seen == nil || (c.per > 0 && now.Sub(c.start) >= c.per) {
+			c.start = now
+			c.seen = map[string]struct{}{}
+			c.reported = false
+		}
+
+		value := safeValue("%v", v)
+		if _, ok := c.seen[value]; ok {
+			continue
+		}
+		if len(c.seen) < c.limit {
+			c.seen[value] = struct{}{}
+			continue
+		}
+
+		if !c.reported {
+			c.reported = true
+			if c.per > 0 {
+				l.diagfLocked("field %q has more than %d distinct values per %s, limiting it", key, c.limit, c.per)
+			} else {
+				l.diagfLocked("field %q has more than %d distinct values, limiting it", key, c.limit)
+			}
+		}
+
+		if !copied {
+			fields = mergeFields(nil, fields)
+			copied = true
+		}
+
+		if c.action == CardinalityHash {
+			h := fnv.New32a()
+			h.Write([]byte(value))
+			fields[key] = "bucket-" + strconv.Itoa(int(h.Sum32()%uint32(c.limit)))
+		} else {
+			delete(fields, key)
+		}
+	}
+
+	return fields
+}
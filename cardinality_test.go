@@ -0,0 +1,83 @@
+package clog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimitFieldCardinality(t *testing.T) {
+	out := bytes.Buffer{}
+	diag := bytes.Buffer{}
+	clock := &manualClock{now: time.Unix(0, 0)}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetDiagnosticsOutput(&diag)
+	l.SetClock(clock)
+	l.SetTimestampMode(TimestampSequence)
+	l.LimitFieldCardinality("user", 2, time.Minute, CardinalityDrop)
+
+	e := l.WithFields(Fields{"user": "carol"})
+	l.InfoKV("login", "user", "alice")
+	l.InfoKV("login", "user", "bob")
+	l.InfoKV("login", "user", "alice")
+	e.Info("login")
+	l.InfoKV("login", "user", "dave")
+
+	clock.now = clock.now.Add(time.Minute)
+	e.Info("login")
+
+	expected := "1 INFO login user=alice\n2 INFO login user=bob\n3 INFO login user=alice\n" +
+		"4 INFO login\n5 INFO login\n6 INFO login user=carol\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+
+	if strings.Count(diag.String(), `field "user" has more than 2 distinct values per 1m0s`) != 1 {
+		t.Errorf("Expected one diagnostics note, got: %s", diag.String())
+	}
+
+	if e.fields["user"] != "carol" {
+		t.Errorf("Entry fields modified")
+	}
+}
+
+func TestLimitFieldCardinalityNoPeriod(t *testing.T) {
+	out := bytes.Buffer{}
+	clock := &manualClock{now: time.Unix(0, 0)}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetDiagnosticsOutput(&bytes.Buffer{})
+	l.SetClock(clock)
+	l.SetTimestampMode(TimestampSequence)
+	l.LimitFieldCardinality("user", 1, 0, CardinalityDrop)
+
+	l.InfoKV("login", "user", "alice")
+	clock.now = clock.now.Add(time.Hour)
+	l.InfoKV("login", "user", "bob")
+
+	if out.String() != "1 INFO login user=alice\n2 INFO login\n" {
+		t.Errorf("Limit without a period not applied: %q", out.String())
+	}
+}
+
+func TestLimitFieldCardinalityHash(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.LimitFieldCardinality("user", 1, time.Minute, CardinalityHash)
+
+	l.InfoKV("login", "user", "alice")
+	l.InfoKV("login", "user", "bob")
+
+	if !strings.Contains(out.String(), " user=alice\n") || !strings.Contains(out.String(), " user=bucket-0\n") {
+		t.Errorf("Unexpected output: %s", out.String())
+	}
+
+	l.LimitFieldCardinality("user", 0, 0, CardinalityDrop)
+	l.InfoKV("login", "user", "bob")
+	if !strings.HasSuffix(out.String(), " user=bob\n") {
+		t.Errorf("Limit not removed: %s", out.String())
+	}
+}
@@ -1,30 +1,218 @@
 /*
 Colorful logger with support for different log levels.
 
-Five log levels are predefined: DEBUG, INFO, WARNING, ERROR and PANIC. The
-logger will only output log messages with level equal to or higher than
-limit specified in setup. The messages can optionally be shown in color
-(turned off by default).
-
-All messages are shown with a RFC3339 timestamp.
+Six log levels are predefined: DEBUG, INFO, WARNING, ERROR, FATAL and
+PANIC. The logger will only output log messages with level equal to or
+higher than limit specified in setup. The messages can optionally be shown
+in color (turned off by default). With SetColorMode(ColorAuto), color is
+only used when the output is a terminal, and not if the NO_COLOR
+environment variable is set or TERM is "dumb". The colors can be changed
+with SetColor() (eg. SetColor(clog.WARNING, clog.Yellow|clog.Bold)), using
+basic, 256-color palette or 24-bit colors, and
+SetColorStyle(ColorLevelOnly) colors just the level name instead of the
+whole line. Colored output can be converted to HTML using ANSIToHTML() or
+NewHTMLWriter().
+
+Messages with a level outside of the predefined ones are reported to the
+diagnostics output (see SetDiagnosticsOutput()) and logged as DEBUG if the
+level is too low, or as ERROR if it's too high.
+
+All messages are shown with a RFC3339 timestamp. SetTimestampMode() can be
+used to switch to RFC3339 with nanoseconds, or to Unix epoch seconds or
+milliseconds instead. SetTimeFormat() sets a custom layout (eg.
+RFC3339Millis, for sub-second precision), and UseUTC(true) shows the
+timestamps in UTC instead of local time. The time is taken from a Clock,
+which can be replaced with SetClock() to get deterministic timestamps in
+tests, or the timestamps can be replaced with message numbers using
+TimestampSequence, for output that is byte-for-byte reproducible (eg. for
+golden files).
+
+SetCaller() enables showing the file and line (and optionally the function)
+from which the message was logged. When logging through wrapper functions,
+SetCallerSkip() can be used to report the wrapper's caller instead.
 
 The logger can be setup directly using Setup(). Alternatively, using
 SetupFromEnv(), the settings can be picked from environment variables
-LOG_LEVEL (should be one of the predefined levle names) and
-LOG_COLOR (should be "true" or "false").
+LOG_LEVEL (should be one of the predefined levle names, a common alias
+such as WARN, or a level number) and LOG_COLOR (should be "true", "false"
+or "auto"). LOG_FORMAT, if set, is either the name of a built-in format
+(eg. json or logfmt, see SetFormat() below), or a layout used to render
+messages (see SetLayout() below).
+
+SetLevel() changes just the level, without resetting the output like
+Setup() does, and can be called at any time. Level() returns the current
+level. LevelHandler() returns a http.Handler showing the level on GET and
+changing it on PUT. HandleSignals() installs a signal handler making the
+logger more or less verbose by one level on each signal.
+
+ParseLevel() returns the level with the given name, and a LogLevel can be
+marshaled to and from text, so it can be used directly in JSON or YAML
+configuration and with flag.TextVar().
+
+SetupFromEnvPrefix() does the same, but prepends a prefix to the variable
+names (eg. MYAPP_LOG_LEVEL) and can use a custom lookup function instead of
+os.Getenv.
+
+Named() returns an Entry whose messages are tagged with a name (eg. "db"),
+and SetModuleLevel() sets a separate level for it, so one component can be
+made more verbose than the rest. The module levels can also be set with
+LOG_LEVEL, after the default level, eg. LOG_LEVEL=INFO,db=DEBUG.
 
 The logger provides Log() function which takes a level, and a message. The
-convenience functions Debug(), Info(), Warning(), Error() and Panic() are
-also provided. There are also variants of these functions which support
+convenience functions Debug(), Info(), Warning(), Error(), Fatal() and Panic()
+are also provided. There are also variants of these functions which support
 passing a format string and arguments instead of a single message string:
-Logf(), Debugf(), Infof(), Warningf(), Errorf() and Panicf().
+Logf(), Debugf(), Infof(), Warningf(), Errorf(), Fatalf() and Panicf().
+
+Structured fields can be attached to messages, either with WithFields(),
+which returns an Entry with the same logging functions, or using the
+key/value variants LogKV(), DebugKV(), InfoKV(), WarningKV(), ErrorKV(),
+FatalKV() and PanicKV(). The fields are shown after the message as
+key=value pairs, sorted by key:
+
+    clog.WithFields(clog.Fields{"user": id, "req": reqID}).Info("login ok")
+    clog.InfoKV("login ok", "user", id, "req", reqID)
+
+NewContext() attaches fields to a context.Context, and FromContext()
+returns an Entry logging with them, so fields like a request ID set at the
+top of a handler show up on every message logged deeper in the call stack:
+
+    ctx = clog.NewContext(ctx, clog.Fields{"req": reqID})
+    ...
+    clog.FromContext(ctx).Info("query done")
+
+LimitFieldCardinality() limits how many distinct values of a field (eg.
+user IDs) are logged per time period, dropping or hashing the values over
+the limit, to protect log storage which indexes the fields.
+
+Using SetFormat(FormatJSON), each message is instead written as a single
+JSON object with the time, level, msg and field keys, which is easier to
+process by log aggregators. FormatECS produces JSON objects with Elastic
+Common Schema field names, for use with Elasticsearch and Kibana, and
+FormatGCP and FormatCloudWatch use the keys expected by Google Cloud Logging
+and CloudWatch Logs Insights.
+FormatLogfmt writes messages in logfmt format, eg.
+time=... level=WARNING msg="disk almost full" free=123.
+FormatColumns lays the text format out in two columns for reading in a
+terminal, wrapping long messages so they stay aligned to the right of the
+timestamp and level.
+
+For full control over the layout, SetFormatter() sets a Formatter which
+renders each Record as a line of output, instead of the built-in format.
+SetLayout() sets a Formatter built from a layout string, to reproduce line
+formats expected by existing tools:
+
+    clog.SetLayout("{time} [{level}] {caller} - {msg}")
+
+Formatting never panics: if formatting a message fails, the format string
+and raw argument values are logged instead. Problems like this are reported
+to the diagnostics output, if one is set with SetDiagnosticsOutput(). With
+SetStrictFormat(true), format string mistakes such as missing or extra
+arguments are reported there as well.
+
+When logging a message with a FATAL level, the logger will call the exit
+handlers registered with RegisterExitHandler() and exit the process with
+status 1. The exit function can be replaced for testing with SetExitFunc().
 
 When logging a message with a PANIC level, the logger will raise a panic
-with the specified message immediately after logging it.
+with the specified message immediately after logging it. For both FATAL and
+PANIC, if a directory is set with SetCrashReportDir(), a JSON crash report
+with the message, stack trace, runtime statistics and build information is
+written there first.
+
+WriterLevel() returns an io.Writer which logs each line written to it with
+the specified level, for use with code that only accepts an io.Writer.
+Writer() does the same and logs the incomplete last line when closed,
+which makes it suitable for the output of subprocesses:
+
+    w := clog.Writer(clog.INFO)
+    cmd.Stdout = w
+    err := cmd.Run()
+    w.Close()
+
+StdLogger() similarly returns a standard library *log.Logger, eg. for
+http.Server.ErrorLog.
+
+NewSlogHandler() returns a log/slog Handler which logs records through clog,
+so code using the standard structured logger shares its level, colors and
+output. Attributes become fields, and slog levels map to the nearest clog
+level (DEBUG, INFO, WARNING or ERROR):
+
+    slog.SetDefault(slog.New(clog.NewSlogHandler()))
+
+Go() and Group run functions in goroutines, logging any panic with its
+stack trace at ERROR level before re-raising it.
+Recover(), called directly by a deferred function, recovers a panic and
+logs it the same way, optionally re-raising it, and RecoverMiddleware()
+does this for HTTP handlers, answering with status 500.
+
+DumpGoroutines() logs the stack traces of all running goroutines, and
+DumpGoroutinesHandler() returns a http.Handler triggering the same on a POST
+request, which is useful for diagnosing hangs. DumpDiagnostics() also logs
+the logger configuration and runtime statistics, and can be triggered by a
+signal installed with HandleDiagnosticsSignal().
+
+StartVolumeReport() periodically logs how many messages and bytes were
+logged per level, and which call sites logged the most, to help keep log
+volume under control.
+
+HTTPMiddleware() wraps a http.Handler, logging the method, path, status
+code, response size and duration of each request, with the status code
+colored by its class. HTTPMiddlewareLevel() logs the requests with a
+different level than INFO.
+
+The HTTP handlers can be protected with a static token using RequireToken(),
+and restricted to read-only requests using ReadOnly().
 
 The output by default goes to os.Stderr. This can be changed by using
-SetOutput(). Note that SetOutput() must be called after Setup() (or
-SetupFromEnv()).
+SetOutput(). OpenSharedFile() opens a log file which several processes can
+safely append to at the same time, and OpenFIFO() opens a named pipe
+without blocking until a reader connects. OpenStream() streams messages to
+a log collector over a Unix socket, reconnecting as needed. Note that
+SetOutput() must be called after Setup() (or SetupFromEnv()). Messages
+logged before that also go to os.Stderr, unless MustSetup() was called, in
+which case they panic. SetLevelOutput() sends messages with a specific
+level to a different writer, eg. to split warnings and errors into
+os.Stderr. SetStderrMirror() additionally mirrors important messages to
+os.Stderr, with a rate limit. With SetAudit(true), every configuration
+change is logged as an INFO message with the old and new values, even
+if the new level is above INFO.
+
+SetFile() writes messages to a log file, rotating it once it reaches a
+size or age limit. Rotated files get the time appended to their name, and
+can be compressed with gzip, keeping only the newest few:
+
+    clog.SetFile("/var/log/app.log", clog.RotateOptions{
+        MaxSize:    100 << 20,
+        MaxBackups: 5,
+        Compress:   true,
+    })
+
+When the file is rotated by an external tool such as logrotate instead,
+Reopen() reopens it at the original path, and HandleReopenSignal() calls
+Reopen() on a signal, usually SIGHUP.
+
+SetSyslog() sends messages to the local syslog daemon or a remote collector
+over UDP or TCP, with an RFC5424 header and the levels mapped to syslog
+severities. The message part uses the logger's format, and changing the
+output closes the connection.
+
+Hooks added with AddHook() are called for each message before it is
+written, and can forward it elsewhere (eg. to an error tracker), change
+its message and fields, or drop it by returning ErrDropRecord. Hooks and
+custom formatters get a copy of the fields, so changing them doesn't
+affect other messages logged with the same Entry.
+
+The package-level functions all use a default logger. Independent loggers,
+with their own level, color and output settings, can be created with New().
+The Logger methods mirror the package-level functions, so different
+components in a program can log at different levels or to different
+outputs. Both the package-level functions and Logger methods are safe to
+call from multiple goroutines, and each message is written to the output
+in one piece. Tests changing the default logger can call Scoped(t) to have
+its configuration restored when the test finishes, and send the output to
+TestWriter(t), which passes messages to t.Log() so they're shown with the
+test and don't break "go test -json" output.
 
 Example use:
 
@@ -34,13 +222,17 @@ Example use:
     clog.Debug("hello world!")
     clog.Warning("Hello")
     clog.Panicf("The end is %s!", "nigh")
+
+    db := clog.New(clog.DEBUG, false)
+    db.SetOutput(dbLogFile)
+    db.Debugf("query took %dms", 42)
 */
 package clog
 
 import (
-	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -53,6 +245,7 @@ const (
 	INFO
 	WARNING
 	ERROR
+	FATAL
 	PANIC
 )
 
@@ -61,6 +254,7 @@ var colorCodes = [PANIC + 1]string{
 	"",
 	"\x1b[33m",
 	"\x1b[31m",
+	"\x1b[1;35m",
 	"\x1b[1;31m",
 }
 
@@ -69,74 +263,190 @@ var levelNames = [PANIC + 1]string{
 	"INFO",
 	"WARNING",
 	"ERROR",
+	"FATAL",
 	"PANIC",
 }
 
 const noColor = "\x1b[0m"
 
-type config struct {
-	level    LogLevel
-	useColor bool
-	output   io.Writer
+type TimestampMode int
+
+// Available timestamp modes
+const (
+	TimestampRFC3339 TimestampMode = iota
+	TimestampRFC3339Nano
+	TimestampEpoch
+	TimestampEpochMillis
+
+	// TimestampSequence replaces the timestamp with the message number,
+	// starting from 1, to make the output reproducible.
+	TimestampSequence
+)
+
+// Clock provides the current time used for message timestamps.
+type Clock interface {
+	Now() time.Time
 }
 
-var cfg config
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
 
 // Setup sets up the logger using provided level and color settings.
 func Setup(level LogLevel, useColor bool) {
-	cfg.level = level
-	cfg.useColor = useColor
-	cfg.output = os.Stderr
+	std.setup("Setup", level, useColor)
+}
+
+// SetLevel changes the log level without touching other settings. It can be
+// called at any time, eg. to make a running program more verbose.
+func SetLevel(level LogLevel) {
+	std.SetLevel(level)
+}
+
+// Level returns the current log level.
+func Level() LogLevel {
+	return std.Level()
 }
 
-// SetOutput sets the output of the logger to go to the specified writer.
+// MustSetup makes logging a message panic if the default logger wasn't set
+// up with Setup(), SetupFromEnv() or SetOutput() first, instead of falling
+// back to os.Stderr. This catches programs logging too early, eg. from init
+// functions.
+func MustSetup() {
+	std.MustSetup()
+}
+
+// SetOutput sets the output of the logger to go to the specified writer. If
+// output is nil, messages go to os.Stderr.
 func SetOutput(output io.Writer) {
-	cfg.output = output
+	std.SetOutput(output)
+}
+
+// SetLevelOutput sets the output for messages with the specified level,
+// overriding the one set with SetOutput(). If output is nil, messages with
+// that level go to the common output again. For example, to send warnings
+// and errors to os.Stderr and everything else to os.Stdout:
+//
+//	clog.SetOutput(os.Stdout)
+//	for level := clog.WARNING; level <= clog.PANIC; level++ {
+//		clog.SetLevelOutput(level, os.Stderr)
+//	}
+func SetLevelOutput(level LogLevel, output io.Writer) {
+	std.SetLevelOutput(level, output)
+}
+
+// SetTimestampMode sets the format of the timestamp shown with each message.
+func SetTimestampMode(mode TimestampMode) {
+	std.SetTimestampMode(mode)
+}
+
+// RFC3339Millis is a timestamp layout for SetTimeFormat(), with
+// millisecond precision.
+const RFC3339Millis = "2006-01-02T15:04:05.000Z07:00"
+
+// SetTimeFormat sets the layout used for message timestamps, as for
+// time.Time.Format(), instead of the timestamp mode. This can be used for
+// timestamps with sub-second precision, eg. with RFC3339Millis. If layout
+// is empty, the timestamp mode is used again. TimestampSequence still
+// replaces the timestamps with message numbers.
+func SetTimeFormat(layout string) {
+	std.SetTimeFormat(layout)
+}
+
+// UseUTC sets whether timestamps are shown in UTC instead of the local
+// time zone.
+func UseUTC(utc bool) {
+	std.UseUTC(utc)
+}
+
+// SetClock sets the clock used for message timestamps. This is useful for
+// getting deterministic timestamps in tests. If clock is nil, the real time
+// is used.
+func SetClock(clock Clock) {
+	std.SetClock(clock)
 }
 
-// SetupFromEnv sets up the logger based on the LOG_LEVEL and LOG_COLOR
-// environment variables.
+// SetupFromEnv sets up the logger based on the LOG_LEVEL, LOG_COLOR and
+// LOG_FORMAT environment variables. LOG_FORMAT, if set, is the name of a
+// built-in format (text, json, ecs, logfmt, gcp, cloudwatch or columns), or
+// a layout as described in NewLayoutFormatter().
 func SetupFromEnv() {
+	SetupFromEnvPrefix("", os.Getenv)
+}
+
+// SetupFromEnvPrefix sets up the logger based on the <prefix>LOG_LEVEL,
+// <prefix>LOG_COLOR and <prefix>LOG_FORMAT variables, as returned by the
+// lookup function. If lookup is nil, os.Getenv is used. Unknown level names
+// and invalid layouts are reported to the diagnostics output and ignored.
+func SetupFromEnvPrefix(prefix string, lookup func(string) string) {
+	if lookup == nil {
+		lookup = os.Getenv
+	}
+
 	l := DEBUG
+	modules := map[string]LogLevel{}
+
+	for _, part := range strings.Split(lookup(prefix+"LOG_LEVEL"), ",") {
+		module, ln := "", part
+		if idx := strings.IndexByte(part, '='); idx >= 0 {
+			module, ln = strings.TrimSpace(part[:idx]), part[idx+1:]
+		}
 
-	ln := strings.ToUpper(os.Getenv("LOG_LEVEL"))
-	c := strings.ToUpper(os.Getenv("LOG_COLOR")) == "TRUE"
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
 
-	for idx, name := range levelNames {
-		if name == ln {
-			l = DEBUG + LogLevel(idx)
-			break
+		level, err := ParseLevel(ln)
+		if err != nil {
+			std.diagf("ignoring %sLOG_LEVEL setting %q: %s", prefix, part, err)
+			continue
+		}
+		if module == "" {
+			l = level
+		} else {
+			modules[module] = level
 		}
 	}
 
-	Setup(l, c)
-}
+	c := strings.ToUpper(lookup(prefix + "LOG_COLOR"))
 
-// Log logs a message with the specified log level.
-func Log(level LogLevel, msg string) {
-	if level < cfg.level || level > PANIC {
-		return
+	std.setup("SetupFromEnv", l, c == "TRUE")
+	if c == "AUTO" {
+		std.setColorMode("SetupFromEnv", ColorAuto)
+	}
+	for module, level := range modules {
+		std.setModuleLevel("SetupFromEnv", module, level)
 	}
 
-	line := fmt.Sprint(time.Now().Format(time.RFC3339), " ", levelNames[level-DEBUG], " ", msg)
-
-	if cfg.useColor {
-		line = fmt.Sprintf("%s%s%s", colorCodes[level-DEBUG], line, noColor)
+	if layout := lookup(prefix + "LOG_FORMAT"); layout != "" {
+		if format, ok := formatNames[strings.ToLower(strings.TrimSpace(layout))]; ok {
+			std.SetFormatter(nil)
+			std.SetFormat(format)
+		} else if err := std.SetLayout(layout); err != nil {
+			std.diagf("ignoring %sLOG_FORMAT setting %q: %s", prefix, layout, err)
+		}
 	}
+}
 
-	fmt.Fprintln(cfg.output, line)
+// Log logs a message with the specified log level.
+func Log(level LogLevel, msg string) {
+	std.Log(level, msg)
+}
 
-	if level >= PANIC {
-		panic(msg)
+func levelName(level LogLevel) string {
+	if level < DEBUG || level > PANIC {
+		return strconv.Itoa(int(level))
 	}
+	return levelNames[level-DEBUG]
 }
 
 // Logf logs a message with the specified log level. The function takes a
 // format string and arguments and passes it through fmt.Sprintf() to get
 // the message string.
 func Logf(level LogLevel, f string, args ...interface{}) {
-	msg := fmt.Sprintf(f, args...)
-	Log(level, msg)
+	std.Logf(level, f, args...)
 }
 
 // Debug is a convenience function equivalent to Log(DEBUG, msg)
@@ -159,6 +469,11 @@ func Error(msg string) {
 	Log(ERROR, msg)
 }
 
+// Fatal is a convenience function equivalent to Log(FATAL, msg)
+func Fatal(msg string) {
+	Log(FATAL, msg)
+}
+
 // Panic is a convenience function equivalent to Log(PANIC, msg)
 func Panic(msg string) {
 	Log(PANIC, msg)
@@ -184,6 +499,11 @@ func Errorf(f string, args ...interface{}) {
 	Logf(ERROR, f, args...)
 }
 
+// Fatalf is a convenience function equivalent to Logf(FATAL, fmt, args...)
+func Fatalf(f string, args ...interface{}) {
+	Logf(FATAL, f, args...)
+}
+
 // Panicf is a convenience function equivalent to Logf(PANIC, fmt, args...)
 func Panicf(f string, args ...interface{}) {
 	Logf(PANIC, f, args...)
@@ -3,6 +3,7 @@ package clog
 import (
 	"bytes"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -10,7 +11,7 @@ import (
 
 func TestSetup(t *testing.T) {
 	Setup(INFO, true)
-	if cfg.level != INFO || cfg.useColor != true {
+	if std.level != INFO || std.useColor != true {
 		t.Errorf("Setup() doesn't set up config correctly")
 	}
 }
@@ -32,11 +33,23 @@ func TestSetupFromEnv(t *testing.T) {
 	os.Setenv("LOG_COLOR", "true")
 
 	SetupFromEnv()
-	if cfg.level != WARNING || cfg.useColor != true {
+	if std.level != WARNING || std.useColor != true {
 		t.Errorf("SetupFromEnv() doesn't set up config correctly")
 	}
 }
 
+func TestSetupFromEnvPrefix(t *testing.T) {
+	env := map[string]string{
+		"MYAPP_LOG_LEVEL": "error",
+		"MYAPP_LOG_COLOR": "false",
+	}
+
+	SetupFromEnvPrefix("MYAPP_", func(key string) string { return env[key] })
+	if std.level != ERROR || std.useColor != false {
+		t.Errorf("SetupFromEnvPrefix() doesn't set up config correctly")
+	}
+}
+
 func TestColorOutput(t *testing.T) {
 	out := bytes.Buffer{}
 
@@ -89,6 +102,107 @@ func TestLogFormat(t *testing.T) {
 	}
 }
 
+func TestTimestampMode(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+	defer SetTimestampMode(TimestampRFC3339)
+
+	SetTimestampMode(TimestampEpochMillis)
+	Log(INFO, "message")
+
+	ts := strings.Split(out.String(), " ")[0]
+	ms, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		t.Fatalf("Incorrect epoch timestamp: %s", ts)
+	}
+
+	if d := time.Since(time.Unix(0, ms*int64(time.Millisecond))); d < 0 || d > time.Minute {
+		t.Errorf("Epoch timestamp too far off: %s", ts)
+	}
+
+	out.Reset()
+	SetTimestampMode(TimestampRFC3339Nano)
+	Log(INFO, "message")
+
+	ts = strings.Split(out.String(), " ")[0]
+	if _, err := time.Parse(time.RFC3339Nano, ts); err != nil {
+		t.Errorf("Incorrect RFC3339Nano timestamp: %s", err)
+	}
+}
+
+func TestTimeFormat(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	zone := time.FixedZone("CET", 3600)
+	l.SetClock(fixedClock(time.Date(2014, 5, 6, 7, 8, 9, 123456789, zone)))
+
+	l.SetTimeFormat(RFC3339Millis)
+	l.Info("local")
+	l.UseUTC(true)
+	l.Info("utc")
+	l.SetTimestampMode(TimestampEpoch)
+	l.SetFormat(FormatJSON)
+	l.Info("json")
+	l.SetTimeFormat("")
+	l.Info("epoch")
+
+	expected := "2014-05-06T07:08:09.123+01:00 INFO local\n" +
+		"2014-05-06T06:08:09.123Z INFO utc\n" +
+		`{"time":"2014-05-06T06:08:09.123Z","level":"INFO","msg":"json"}` + "\n" +
+		`{"time":1399356489,"level":"INFO","msg":"epoch"}` + "\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected timestamps: %q", out.String())
+	}
+}
+
+func TestTimestampSequence(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetTimestampMode(TimestampSequence)
+
+	l.Info("first")
+	l.Debug("second")
+	l.SetFormat(FormatJSON)
+	l.Info("third")
+
+	expected := "1 INFO first\n2 DEBUG second\n" + `{"time":3,"level":"INFO","msg":"third"}` + "\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected sequence output: %q", out.String())
+	}
+
+	out.Reset()
+	l.SetTimestampMode(TimestampSequence)
+	l.Info("again")
+	if !strings.HasPrefix(out.String(), `{"time":1,`) {
+		t.Errorf("Sequence not reset: %s", out.String())
+	}
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestSetClock(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+	defer SetClock(nil)
+
+	SetClock(fixedClock(time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC)))
+	Log(INFO, "message")
+
+	if out.String() != "2014-05-06T07:08:09Z INFO message\n" {
+		t.Errorf("Incorrect timestamp with fixed clock: %s", out.String())
+	}
+}
+
 func TestPanic(t *testing.T) {
 	out := bytes.Buffer{}
 	Setup(DEBUG, false)
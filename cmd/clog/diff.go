@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var levelNames = map[string]bool{
+	"DEBUG":   true,
+	"INFO":    true,
+	"WARNING": true,
+	"ERROR":   true,
+	"FATAL":   true,
+	"PANIC":   true,
+}
+
+type entry struct {
+	level string
+	msg   string
+}
+
+// logRun holds the messages of one log file, grouped by fingerprint.
+type logRun struct {
+	order   []string
+	entries map[string][]entry
+}
+
+// fingerprint masks out numbers in the message so that messages differing
+// only in counts, IDs or durations are treated as the same message.
+func fingerprint(msg string) string {
+	b := strings.Builder{}
+	inNumber := false
+
+	for _, r := range msg {
+		if r >= '0' && r <= '9' {
+			if !inNumber {
+				b.WriteByte('#')
+				inNumber = true
+			}
+			continue
+		}
+		inNumber = false
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// parseLine splits a log line into level and message. Lines which don't
+// look like log messages (eg. continuation lines of stack traces) are
+// skipped.
+func parseLine(line string) (entry, bool) {
+	parts := strings.SplitN(strings.TrimRight(line, "\r"), " ", 3)
+	if len(parts) < 2 || !levelNames[parts[1]] {
+		return entry{}, false
+	}
+
+	e := entry{level: parts[1]}
+	if len(parts) == 3 {
+		e.msg = parts[2]
+	}
+
+	return e, true
+}
+
+func readRun(r io.Reader) (*logRun, error) {
+	run := &logRun{entries: map[string][]entry{}}
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1024*1024)
+
+	for s.Scan() {
+		e, ok := parseLine(s.Text())
+		if !ok {
+			continue
+		}
+
+		fp := fingerprint(e.msg)
+		if _, seen := run.entries[fp]; !seen {
+			run.order = append(run.order, fp)
+		}
+		run.entries[fp] = append(run.entries[fp], e)
+	}
+
+	return run, s.Err()
+}
+
+func readRunFile(name string) (*logRun, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	run, err := readRun(f)
+	if err == nil && len(run.order) == 0 {
+		err = fmt.Errorf("%s: no messages in the text format found", name)
+	}
+	return run, err
+}
+
+// common returns the fingerprints of the run that also appear in the
+// other run, in order.
+func common(run, other *logRun) []string {
+	var fps []string
+
+	for _, fp := range run.order {
+		if _, ok := other.entries[fp]; ok {
+			fps = append(fps, fp)
+		}
+	}
+
+	return fps
+}
+
+// aligned returns the fingerprints in the longest common subsequence of a
+// and b. Common fingerprints outside it were moved relative to the others.
+func aligned(a, b []string) map[string]bool {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	in := map[string]bool{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			in[a[i]] = true
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return in
+}
+
+// ranks returns the position of each fingerprint in fps.
+func ranks(fps []string) map[string]int {
+	r := make(map[string]int, len(fps))
+	for i, fp := range fps {
+		r[fp] = i
+	}
+	return r
+}
+
+// diffRuns writes the differences between two runs to out, and returns
+// the number of differences found.
+func diffRuns(a, b *logRun, out io.Writer) int {
+	n := 0
+	aCommon, bCommon := common(a, b), common(b, a)
+	inOrder := aligned(aCommon, bCommon)
+	aRanks, bRanks := ranks(aCommon), ranks(bCommon)
+
+	for _, fp := range a.order {
+		ea := a.entries[fp]
+		eb, ok := b.entries[fp]
+
+		if !ok {
+			fmt.Fprintf(out, "- %s %s (x%d)\n", ea[0].level, fp, len(ea))
+			n++
+			continue
+		}
+
+		if ea[0].level != eb[0].level {
+			fmt.Fprintf(out, "~ %s -> %s %s\n", ea[0].level, eb[0].level, fp)
+			n++
+		}
+
+		if len(ea) != len(eb) {
+			fmt.Fprintf(out, "# %s %s (x%d -> x%d)\n", eb[0].level, fp, len(ea), len(eb))
+			n++
+		}
+
+		if !inOrder[fp] {
+			fmt.Fprintf(out, "> %s %s (position %d -> %d)\n", eb[0].level, fp, aRanks[fp]+1, bRanks[fp]+1)
+			n++
+		}
+	}
+
+	for _, fp := range b.order {
+		if _, ok := a.entries[fp]; !ok {
+			eb := b.entries[fp]
+			fmt.Fprintf(out, "+ %s %s (x%d)\n", eb[0].level, fp, len(eb))
+			n++
+		}
+	}
+
+	return n
+}
+
+func runDiff(name1, name2 string, out io.Writer) int {
+	a, err := readRunFile(name1)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	b, err := readRunFile(name2)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if diffRuns(a, b, out) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	fp := fingerprint("retry 3 of 10 after 250ms")
+	if fp != "retry # of # after #ms" {
+		t.Errorf("Incorrect fingerprint: %s", fp)
+	}
+}
+
+func TestDiffRuns(t *testing.T) {
+	run1 := `2014-05-06T07:08:09Z INFO starting worker 1
+2014-05-06T07:08:09Z INFO starting worker 2
+2014-05-06T07:08:10Z WARNING disk almost full
+goroutine 1 [running]:
+2014-05-06T07:08:11Z INFO cache warmed
+2014-05-06T07:08:12Z INFO connected to db
+2014-05-06T07:08:13Z INFO shutting down
+`
+	run2 := `2014-05-07T07:08:09Z INFO starting worker 1
+2014-05-07T07:08:10Z ERROR disk almost full
+2014-05-07T07:08:11Z INFO connected to db
+2014-05-07T07:08:12Z INFO cache warmed
+2014-05-07T07:08:13Z WARNING slow query took 1234ms
+`
+	a, _ := readRun(strings.NewReader(run1))
+	b, _ := readRun(strings.NewReader(run2))
+
+	out := bytes.Buffer{}
+	n := diffRuns(a, b, &out)
+
+	expected := `# INFO starting worker # (x2 -> x1)
+~ WARNING -> ERROR disk almost full
+> INFO cache warmed (position 3 -> 4)
+- INFO shutting down (x1)
++ WARNING slow query took #ms (x1)
+`
+	if out.String() != expected {
+		t.Errorf("Incorrect diff output:\n%s", out.String())
+	}
+
+	if n != 5 {
+		t.Errorf("Expected 5 differences, got %d", n)
+	}
+}
+
+func TestDiffRunsMoved(t *testing.T) {
+	a, _ := readRun(strings.NewReader("t INFO a\nt INFO b\nt INFO c\nt INFO d\n"))
+	b, _ := readRun(strings.NewReader("t INFO b\nt INFO c\nt INFO d\nt INFO a\n"))
+
+	out := bytes.Buffer{}
+	if n := diffRuns(a, b, &out); n != 1 || out.String() != "> INFO a (position 1 -> 4)\n" {
+		t.Errorf("Expected only the shifted message as moved, got %d:\n%s", n, out.String())
+	}
+}
+
+func TestRunDiffUnparseable(t *testing.T) {
+	dir := t.TempDir()
+	json1 := filepath.Join(dir, "run1.log")
+	json2 := filepath.Join(dir, "run2.log")
+	os.WriteFile(json1, []byte(`{"level":"INFO","msg":"started"}`+"\n"), 0644)
+	os.WriteFile(json2, []byte(`{"level":"ERROR","msg":"failed"}`+"\n"), 0644)
+
+	if status := runDiff(json1, json2, io.Discard); status != 2 {
+		t.Errorf("Expected status 2 for files without text messages, got %d", status)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Command clog provides tools for working with clog output.
+
+Usage:
+
+    clog diff run1.log run2.log
+
+The diff command compares two log files, aligning messages by their
+fingerprint (the message text with numbers masked out), and reports
+messages that were added, removed, moved relative to other messages or
+logged with a different level. Only the text format is supported. The
+exit status is 1 if any differences were found, and 2 on errors,
+including a file without any messages in the text format.
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: clog diff run1.log run2.log")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "diff":
+		if len(os.Args) != 4 {
+			usage()
+		}
+		os.Exit(runDiff(os.Args[2], os.Args[3], os.Stdout))
+	default:
+		usage()
+	}
+}
@@ -0,0 +1,223 @@
+package clog
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ColorMode specifies when messages are shown in color.
+type ColorMode int
+
+// Available color modes
+const (
+	ColorOff ColorMode = iota
+	ColorOn
+	// ColorAuto uses color only if the output is a terminal, unless the
+	// NO_COLOR environment variable is set or TERM is "dumb".
+	ColorAuto
+)
+
+// SetColorMode sets when the default logger shows messages in color.
+func SetColorMode(mode ColorMode) {
+	std.SetColorMode(mode)
+}
+
+// SetColorMode sets when the logger shows messages in color.
+func (l *Logger) SetColorMode(mode ColorMode) {
+	l.setColorMode("SetColorMode", mode)
+}
+
+func (l *Logger) setColorMode(source string, mode ColorMode) {
+	l.mu.Lock()
+	old := l.colorMode()
+	l.useColor = mode == ColorOn
+	l.colorAuto = mode == ColorAuto && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+	l.ttyFile = nil
+	l.mu.Unlock()
+
+	l.auditChange(source, "color", colorModeName(old), colorModeName(mode))
+}
+
+// colorMode returns the current color mode. It must be called with the
+// lock held.
+func (l *Logger) colorMode() ColorMode {
+	switch {
+	case l.useColor:
+		return ColorOn
+	case l.colorAuto:
+		return ColorAuto
+	default:
+		return ColorOff
+	}
+}
+
+func colorModeName(mode ColorMode) string {
+	switch mode {
+	case ColorOn:
+		return "true"
+	case ColorAuto:
+		return "auto"
+	default:
+		return "false"
+	}
+}
+
+// isTerminal reports whether the output is a terminal, remembering the
+// result for the last file checked. It must be called with the lock held.
+func (l *Logger) isTerminal(output io.Writer) bool {
+	f, ok := output.(*os.File)
+	if !ok {
+		return false
+	}
+
+	if f != l.ttyFile {
+		fi, err := f.Stat()
+		l.ttyFile = f
+		l.tty = err == nil && fi.Mode()&os.ModeCharDevice != 0
+	}
+	return l.tty
+}
+
+// Color is a text color and style for SetColor(). Colors can be combined
+// with the Bold, Underline and Bright attributes, eg. Yellow|Bold.
+type Color uint64
+
+const (
+	colorBasic Color = 1 << (32 + iota)
+	color256
+	colorRGB
+
+	// Bold shows the text in bold.
+	Bold
+	// Underline underlines the text.
+	Underline
+	// Bright uses the bright variant of a basic color.
+	Bright
+)
+
+// Available basic colors
+const (
+	Black Color = colorBasic | iota
+	Red
+	Green
+	Yellow
+	Blue
+	Magenta
+	Cyan
+	White
+)
+
+// Color256 returns a color from the 256-color palette supported by most
+// terminals.
+func Color256(n uint8) Color {
+	return color256 | Color(n)
+}
+
+// RGB returns a 24-bit color, supported by many terminals.
+func RGB(r, g, b uint8) Color {
+	return colorRGB | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// code returns the ANSI escape sequence for the color.
+func (c Color) code() string {
+	var params []string
+	if c&Bold != 0 {
+		params = append(params, "1")
+	}
+	if c&Underline != 0 {
+		params = append(params, "4")
+	}
+
+	switch {
+	case c&colorRGB != 0:
+		params = append(params, "38", "2", strconv.Itoa(int(c>>16&0xff)),
+			strconv.Itoa(int(c>>8&0xff)), strconv.Itoa(int(c&0xff)))
+	case c&color256 != 0:
+		params = append(params, "38", "5", strconv.Itoa(int(c&0xff)))
+	case c&colorBasic != 0 && c&Bright != 0:
+		params = append(params, strconv.Itoa(90+int(c&7)))
+	case c&colorBasic != 0:
+		params = append(params, strconv.Itoa(30+int(c&7)))
+	}
+
+	if len(params) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// SetColor sets the color used for messages with the specified level. A
+// zero Color shows the messages without color.
+func SetColor(level LogLevel, color Color) {
+	std.SetColor(level, color)
+}
+
+// SetColor sets the color used for messages with the specified level.
+func (l *Logger) SetColor(level LogLevel, color Color) {
+	if level < DEBUG || level > PANIC {
+		return
+	}
+
+	l.mu.Lock()
+	colors := colorCodes
+	if l.colors != nil {
+		colors = *l.colors
+	}
+	colors[level-DEBUG] = color.code()
+	l.colors = &colors
+	l.mu.Unlock()
+}
+
+// ColorStyle specifies which part of the message is colored.
+type ColorStyle int
+
+// Available color styles
+const (
+	ColorLine ColorStyle = iota
+	ColorLevelOnly
+)
+
+// SetColorStyle sets which part of colored messages is shown in color: the
+// whole line (the default), or only the level name.
+func SetColorStyle(style ColorStyle) {
+	std.SetColorStyle(style)
+}
+
+// SetColorStyle sets which part of colored messages is shown in color.
+func (l *Logger) SetColorStyle(style ColorStyle) {
+	l.mu.Lock()
+	l.colorLevelOnly = style == ColorLevelOnly
+	l.mu.Unlock()
+}
+
+// colorText returns the text in the color if messages with the level are
+// shown in color, restoring the line color after it. Otherwise the text
+// is returned unchanged.
+func (l *Logger) colorText(level LogLevel, text string, color Color) string {
+	if level < DEBUG || level > PANIC {
+		return text
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	output := l.output
+	if w := l.levelOut[level-DEBUG]; w != nil {
+		output = w
+	}
+	if l.formatter != nil || !l.isText() ||
+		!(l.useColor || (l.colorAuto && output != nil && l.isTerminal(output))) {
+		return text
+	}
+
+	restore := colorCodes[level-DEBUG]
+	if l.colors != nil {
+		restore = l.colors[level-DEBUG]
+	}
+	if l.colorLevelOnly || restore == "" {
+		restore = noColor
+	}
+	return color.code() + text + restore
+}
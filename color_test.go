@@ -0,0 +1,125 @@
+package clog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestColorAuto(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "app.log"))
+	if err != nil {
+		t.Fatalf("Can't create file: %s", err)
+	}
+	defer f.Close()
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm")
+
+	l := New(DEBUG, false)
+	l.SetOutput(f)
+	l.SetColorMode(ColorAuto)
+	l.Warning("to a file")
+
+	data, _ := os.ReadFile(f.Name())
+	if strings.Contains(string(data), "\x1b[") {
+		t.Errorf("Color used for a regular file: %q", data)
+	}
+
+	if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+		l.isTerminal(tty)
+		if !l.tty {
+			t.Errorf("Terminal not detected")
+		}
+		tty.Close()
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	l.SetColorMode(ColorAuto)
+	if l.colorAuto {
+		t.Errorf("NO_COLOR not honored")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "dumb")
+	l.SetColorMode(ColorAuto)
+	if l.colorAuto {
+		t.Errorf("TERM=dumb not honored")
+	}
+
+	l.SetColorMode(ColorOn)
+	if !l.useColor || l.colorAuto {
+		t.Errorf("ColorOn not set correctly")
+	}
+}
+
+func TestSetupFromEnvColorAuto(t *testing.T) {
+	Scoped(t)
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm")
+
+	env := map[string]string{"LOG_COLOR": "auto"}
+	SetupFromEnvPrefix("", func(key string) string { return env[key] })
+
+	if std.useColor || !std.colorAuto {
+		t.Errorf("LOG_COLOR=auto not set up correctly")
+	}
+}
+
+func TestColorCodes(t *testing.T) {
+	codes := map[Color]string{
+		Blue:                   colorCodes[DEBUG],
+		Yellow:                 colorCodes[WARNING],
+		Red:                    colorCodes[ERROR],
+		Magenta | Bold:         colorCodes[FATAL],
+		Red | Bold:             colorCodes[PANIC],
+		Yellow | Bright:        "\x1b[93m",
+		Cyan | Underline:       "\x1b[4;36m",
+		Color256(208):          "\x1b[38;5;208m",
+		RGB(255, 128, 0):       "\x1b[38;2;255;128;0m",
+		Color256(208) | Bold:   "\x1b[1;38;5;208m",
+		0:                      "",
+		Bold | Underline | Red: "\x1b[1;4;31m",
+	}
+
+	for color, code := range codes {
+		if color.code() != code {
+			t.Errorf("Expected %q, got %q", code, color.code())
+		}
+	}
+}
+
+func TestSetColor(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, true)
+	l.SetOutput(&out)
+	l.SetTimestampMode(TimestampSequence)
+	l.SetColor(DEBUG, Color256(250))
+	l.SetColor(INFO, Green)
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Warning("warning")
+
+	expected := "\x1b[38;5;250m1 DEBUG debug\x1b[0m\n\x1b[32m2 INFO info\x1b[0m\n\x1b[33m3 WARNING warning\x1b[0m\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected colors: %q", out.String())
+	}
+
+	out.Reset()
+	l.SetColor(INFO, 0)
+	l.SetColorStyle(ColorLevelOnly)
+	l.Warning("warning")
+	l.Info("info")
+
+	expected = "4 \x1b[33mWARNING\x1b[0m warning\n5 INFO info\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected level-only colors: %q", out.String())
+	}
+
+	if colorCodes[DEBUG] != "\x1b[34m" {
+		t.Errorf("SetColor() changed the default colors")
+	}
+}
@@ -0,0 +1,73 @@
+package clog
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// levelWidth is the width of the longest level name.
+const levelWidth = len("WARNING")
+
+// terminalWidth returns the terminal width from the COLUMNS environment
+// variable, or 80 if it isn't set.
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+// formatColumns renders the text in a column to the right of the gutter,
+// wrapping it at the width. Lines after the first are indented by the
+// gutter width, so the text stays aligned.
+func formatColumns(gutter string, gutterWidth int, text string, width int) string {
+	avail := width - gutterWidth
+	if avail < 20 {
+		avail = 20
+	}
+
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		lines = append(lines, wrapText(para, avail)...)
+	}
+
+	indent := strings.Repeat(" ", gutterWidth)
+	return gutter + strings.Join(lines, "\n"+indent)
+}
+
+// wrapText splits the text into lines of at most width characters, breaking
+// at spaces where possible.
+func wrapText(text string, width int) []string {
+	var lines []string
+	line, n := "", 0
+
+	for _, word := range strings.Fields(text) {
+		wn := utf8.RuneCountInString(word)
+
+		if n > 0 && n+1+wn > width {
+			lines = append(lines, line)
+			line, n = "", 0
+		}
+
+		for wn > width {
+			if n > 0 {
+				lines = append(lines, line)
+				line, n = "", 0
+			}
+			split := len(string([]rune(word)[:width]))
+			lines = append(lines, word[:split])
+			word, wn = word[split:], wn-width
+		}
+
+		if n > 0 {
+			line += " "
+			n++
+		}
+		line += word
+		n += wn
+	}
+
+	return append(lines, line)
+}
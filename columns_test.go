@@ -0,0 +1,49 @@
+package clog
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	for _, c := range []struct {
+		text  string
+		width int
+		lines []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"abcdefghijklmnop xy", 6, []string{"abcdef", "ghijkl", "mnop", "xy"}},
+		{"žžžžžž", 4, []string{"žžžž", "žž"}},
+		{"", 10, []string{""}},
+	} {
+		if lines := wrapText(c.text, c.width); !reflect.DeepEqual(lines, c.lines) {
+			t.Errorf("wrapText(%q, %d) = %q, expected %q", c.text, c.width, lines, c.lines)
+		}
+	}
+}
+
+func TestFormatColumns(t *testing.T) {
+	t.Setenv("COLUMNS", "40")
+
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetTimestampMode(TimestampSequence)
+	l.SetFormat(FormatColumns)
+
+	l.InfoKV("connection to the database was lost, retrying", "attempt", 2)
+	l.Warning("first line\nsecond line")
+
+	expected := strings.Join([]string{
+		"1 INFO    connection to the database was",
+		"          lost, retrying attempt=2",
+		"2 WARNING first line",
+		"          second line",
+	}, "\n") + "\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected columns output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,321 @@
+/*
+Package logrus provides the most used parts of the logrus API, backed by
+clog, to help migrating code using github.com/sirupsen/logrus. For most
+code, changing the import is enough:
+
+    import log "github.com/senko/clog/compat/logrus"
+
+    log.WithFields(log.Fields{"user": id}).Info("login ok")
+
+The logrus levels map to clog levels as follows:
+
+    TraceLevel, DebugLevel   DEBUG
+    InfoLevel                INFO
+    WarnLevel                WARNING
+    ErrorLevel               ERROR
+    FatalLevel               FATAL
+    PanicLevel               PANIC
+
+Formatters, hooks and the Logger type of logrus are not provided; use the
+corresponding clog functions (SetFormat(), AddHook(), New()) instead.
+*/
+package logrus
+
+import (
+	"fmt"
+
+	"github.com/senko/clog"
+)
+
+// Fields holds structured key/value data attached to a log message.
+type Fields = clog.Fields
+
+// ErrorKey is the field used by WithError.
+const ErrorKey = "error"
+
+// Level is a logrus log level.
+type Level uint32
+
+// Available log levels, in the logrus order
+const (
+	PanicLevel Level = iota
+	FatalLevel
+	ErrorLevel
+	WarnLevel
+	InfoLevel
+	DebugLevel
+	TraceLevel
+)
+
+var clogLevels = [TraceLevel + 1]clog.LogLevel{
+	clog.PANIC,
+	clog.FATAL,
+	clog.ERROR,
+	clog.WARNING,
+	clog.INFO,
+	clog.DEBUG,
+	clog.DEBUG,
+}
+
+// SetLevel sets the level of the default clog logger.
+func SetLevel(level Level) {
+	if level > TraceLevel {
+		level = TraceLevel
+	}
+	clog.SetLevel(clogLevels[level])
+}
+
+// GetLevel returns the level of the default clog logger.
+func GetLevel() Level {
+	switch clog.Level() {
+	case clog.DEBUG:
+		return DebugLevel
+	case clog.INFO:
+		return InfoLevel
+	case clog.WARNING:
+		return WarnLevel
+	case clog.ERROR:
+		return ErrorLevel
+	case clog.FATAL:
+		return FatalLevel
+	default:
+		return PanicLevel
+	}
+}
+
+// Entry is a set of fields to log messages with.
+type Entry struct {
+	e *clog.Entry
+}
+
+// WithField returns an entry with the field added.
+func (e *Entry) WithField(key string, value interface{}) *Entry {
+	return &Entry{e.e.WithFields(Fields{key: value})}
+}
+
+// WithFields returns an entry with the fields added.
+func (e *Entry) WithFields(fields Fields) *Entry {
+	return &Entry{e.e.WithFields(fields)}
+}
+
+// WithError returns an entry with the error added as the "error" field.
+func (e *Entry) WithError(err error) *Entry {
+	return e.WithField(ErrorKey, err)
+}
+
+// Log logs the message with the level, formatted as with fmt.Sprint.
+func (e *Entry) Log(level Level, args ...interface{}) {
+	if level > TraceLevel {
+		level = TraceLevel
+	}
+	e.e.Log(clogLevels[level], fmt.Sprint(args...))
+}
+
+// Logf logs the message with the level, formatted as with fmt.Sprintf.
+func (e *Entry) Logf(level Level, format string, args ...interface{}) {
+	if level > TraceLevel {
+		level = TraceLevel
+	}
+	e.e.Logf(clogLevels[level], format, args...)
+}
+
+// Trace logs the message with DEBUG level.
+func (e *Entry) Trace(args ...interface{}) {
+	e.Log(TraceLevel, args...)
+}
+
+// Debug logs the message with DEBUG level.
+func (e *Entry) Debug(args ...interface{}) {
+	e.Log(DebugLevel, args...)
+}
+
+// Info logs the message with INFO level.
+func (e *Entry) Info(args ...interface{}) {
+	e.Log(InfoLevel, args...)
+}
+
+// Print logs the message with INFO level.
+func (e *Entry) Print(args ...interface{}) {
+	e.Log(InfoLevel, args...)
+}
+
+// Warn logs the message with WARNING level.
+func (e *Entry) Warn(args ...interface{}) {
+	e.Log(WarnLevel, args...)
+}
+
+// Warning logs the message with WARNING level.
+func (e *Entry) Warning(args ...interface{}) {
+	e.Log(WarnLevel, args...)
+}
+
+// Error logs the message with ERROR level.
+func (e *Entry) Error(args ...interface{}) {
+	e.Log(ErrorLevel, args...)
+}
+
+// Fatal logs the message with FATAL level, and exits the process.
+func (e *Entry) Fatal(args ...interface{}) {
+	e.Log(FatalLevel, args...)
+}
+
+// Panic logs the message with PANIC level, and panics.
+func (e *Entry) Panic(args ...interface{}) {
+	e.Log(PanicLevel, args...)
+}
+
+// Tracef logs the formatted message with DEBUG level.
+func (e *Entry) Tracef(format string, args ...interface{}) {
+	e.Logf(TraceLevel, format, args...)
+}
+
+// Debugf logs the formatted message with DEBUG level.
+func (e *Entry) Debugf(format string, args ...interface{}) {
+	e.Logf(DebugLevel, format, args...)
+}
+
+// Infof logs the formatted message with INFO level.
+func (e *Entry) Infof(format string, args ...interface{}) {
+	e.Logf(InfoLevel, format, args...)
+}
+
+// Printf logs the formatted message with INFO level.
+func (e *Entry) Printf(format string, args ...interface{}) {
+	e.Logf(InfoLevel, format, args...)
+}
+
+// Warnf logs the formatted message with WARNING level.
+func (e *Entry) Warnf(format string, args ...interface{}) {
+	e.Logf(WarnLevel, format, args...)
+}
+
+// Warningf logs the formatted message with WARNING level.
+func (e *Entry) Warningf(format string, args ...interface{}) {
+	e.Logf(WarnLevel, format, args...)
+}
+
+// Errorf logs the formatted message with ERROR level.
+func (e *Entry) Errorf(format string, args ...interface{}) {
+	e.Logf(ErrorLevel, format, args...)
+}
+
+// Fatalf logs the formatted message with FATAL level, and exits the process.
+func (e *Entry) Fatalf(format string, args ...interface{}) {
+	e.Logf(FatalLevel, format, args...)
+}
+
+// Panicf logs the formatted message with PANIC level, and panics.
+func (e *Entry) Panicf(format string, args ...interface{}) {
+	e.Logf(PanicLevel, format, args...)
+}
+
+func std() *Entry {
+	return &Entry{clog.WithFields(nil)}
+}
+
+// WithField returns an entry with the field, using the default clog logger.
+func WithField(key string, value interface{}) *Entry {
+	return std().WithField(key, value)
+}
+
+// WithFields returns an entry with the fields, using the default clog
+// logger.
+func WithFields(fields Fields) *Entry {
+	return std().WithFields(fields)
+}
+
+// WithError returns an entry with the error as the "error" field, using the
+// default clog logger.
+func WithError(err error) *Entry {
+	return std().WithError(err)
+}
+
+// Trace logs the message with DEBUG level.
+func Trace(args ...interface{}) {
+	std().Trace(args...)
+}
+
+// Debug logs the message with DEBUG level.
+func Debug(args ...interface{}) {
+	std().Debug(args...)
+}
+
+// Info logs the message with INFO level.
+func Info(args ...interface{}) {
+	std().Info(args...)
+}
+
+// Print logs the message with INFO level.
+func Print(args ...interface{}) {
+	std().Print(args...)
+}
+
+// Warn logs the message with WARNING level.
+func Warn(args ...interface{}) {
+	std().Warn(args...)
+}
+
+// Warning logs the message with WARNING level.
+func Warning(args ...interface{}) {
+	std().Warning(args...)
+}
+
+// Error logs the message with ERROR level.
+func Error(args ...interface{}) {
+	std().Error(args...)
+}
+
+// Fatal logs the message with FATAL level, and exits the process.
+func Fatal(args ...interface{}) {
+	std().Fatal(args...)
+}
+
+// Panic logs the message with PANIC level, and panics.
+func Panic(args ...interface{}) {
+	std().Panic(args...)
+}
+
+// Tracef logs the formatted message with DEBUG level.
+func Tracef(format string, args ...interface{}) {
+	std().Tracef(format, args...)
+}
+
+// Debugf logs the formatted message with DEBUG level.
+func Debugf(format string, args ...interface{}) {
+	std().Debugf(format, args...)
+}
+
+// Infof logs the formatted message with INFO level.
+func Infof(format string, args ...interface{}) {
+	std().Infof(format, args...)
+}
+
+// Printf logs the formatted message with INFO level.
+func Printf(format string, args ...interface{}) {
+	std().Printf(format, args...)
+}
+
+// Warnf logs the formatted message with WARNING level.
+func Warnf(format string, args ...interface{}) {
+	std().Warnf(format, args...)
+}
+
+// Warningf logs the formatted message with WARNING level.
+func Warningf(format string, args ...interface{}) {
+	std().Warningf(format, args...)
+}
+
+// Errorf logs the formatted message with ERROR level.
+func Errorf(format string, args ...interface{}) {
+	std().Errorf(format, args...)
+}
+
+// Fatalf logs the formatted message with FATAL level, and exits the process.
+func Fatalf(format string, args ...interface{}) {
+	std().Fatalf(format, args...)
+}
+
+// Panicf logs the formatted message with PANIC level, and panics.
+func Panicf(format string, args ...interface{}) {
+	std().Panicf(format, args...)
+}
@@ -0,0 +1,41 @@
+package logrus
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/senko/clog"
+)
+
+func TestLogrus(t *testing.T) {
+	out := bytes.Buffer{}
+	clog.Scoped(t)
+	clog.SetOutput(&out)
+	clog.SetCaller(true, false)
+	SetLevel(InfoLevel)
+
+	WithField("user", 42).WithError(errors.New("denied")).Warnf("login %s", "failed")
+	Debug("hidden")
+	Info("count: ", 3)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Unexpected output: %s", out.String())
+	}
+	if !strings.HasSuffix(lines[0], " WARNING logrus/logrus_test.go:19 login failed error=denied user=42") {
+		t.Errorf("Unexpected message: %s", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], " INFO logrus/logrus_test.go:21 count: 3") {
+		t.Errorf("Unexpected message: %s", lines[1])
+	}
+
+	if GetLevel() != InfoLevel {
+		t.Errorf("GetLevel() returned %d", GetLevel())
+	}
+	SetLevel(TraceLevel)
+	if clog.Level() != clog.DEBUG || GetLevel() != DebugLevel {
+		t.Errorf("TraceLevel not mapped to DEBUG")
+	}
+}
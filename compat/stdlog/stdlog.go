@@ -0,0 +1,269 @@
+/*
+Package stdlog provides the logging functions of the standard log package,
+backed by clog, to help migrating code using the standard library logger.
+Changing the import from "log" to "github.com/senko/clog/compat/stdlog"
+(named log) is enough for most code:
+
+    import log "github.com/senko/clog/compat/stdlog"
+
+The functions map to clog levels as follows:
+
+    Print, Printf, Println   INFO
+    Fatal, Fatalf, Fatalln   FATAL (exits the process)
+    Panic, Panicf, Panicln   PANIC (panics)
+    Output                   INFO
+
+The timestamp and other decorations are added by clog, so the flags set
+with SetFlags() are only stored to be returned by Flags(). The prefix set
+with SetPrefix() is prepended to each message, as with the Lmsgprefix flag.
+*/
+package stdlog
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/senko/clog"
+)
+
+// The flags of the log package, accepted by SetFlags() and New(). They
+// don't change the output, which is formatted by clog.
+const (
+	Ldate         = log.Ldate
+	Ltime         = log.Ltime
+	Lmicroseconds = log.Lmicroseconds
+	Llongfile     = log.Llongfile
+	Lshortfile    = log.Lshortfile
+	LUTC          = log.LUTC
+	Lmsgprefix    = log.Lmsgprefix
+	LstdFlags     = log.LstdFlags
+)
+
+// Logger provides the methods of log.Logger using a clog Logger.
+type Logger struct {
+	l *clog.Logger
+
+	mu     sync.Mutex
+	prefix string
+	flags  int
+}
+
+// std uses the default clog logger.
+var std = &Logger{flags: LstdFlags}
+
+// New returns a Logger writing to out, with the prefix prepended to each
+// message, as log.New() does. The messages are formatted by a new clog
+// Logger with DEBUG level and no colors.
+func New(out io.Writer, prefix string, flag int) *Logger {
+	l := clog.New(clog.DEBUG, false)
+	l.SetOutput(out)
+	return &Logger{l: l, prefix: prefix, flags: flag}
+}
+
+// Wrap returns a Logger logging to the clog logger.
+func Wrap(l *clog.Logger) *Logger {
+	return &Logger{l: l}
+}
+
+// Default returns the Logger used by the package-level functions, which
+// logs to the default clog logger.
+func Default() *Logger {
+	return std
+}
+
+func sprintln(v []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(v...), "\n")
+}
+
+func (l *Logger) log(level clog.LogLevel, msg string) {
+	l.mu.Lock()
+	msg = l.prefix + msg
+	l.mu.Unlock()
+
+	if l.l == nil {
+		clog.Log(level, msg)
+	} else {
+		l.l.Log(level, msg)
+	}
+}
+
+// SetOutput sets the output of the clog logger.
+func (l *Logger) SetOutput(w io.Writer) {
+	if l.l == nil {
+		clog.SetOutput(w)
+	} else {
+		l.l.SetOutput(w)
+	}
+}
+
+// Writer returns a writer logging each line written to it with INFO level.
+func (l *Logger) Writer() io.Writer {
+	if l.l == nil {
+		return clog.WriterLevel(clog.INFO)
+	}
+	return l.l.WriterLevel(clog.INFO)
+}
+
+// SetPrefix sets the prefix prepended to each message.
+func (l *Logger) SetPrefix(prefix string) {
+	l.mu.Lock()
+	l.prefix = prefix
+	l.mu.Unlock()
+}
+
+// Prefix returns the prefix prepended to each message.
+func (l *Logger) Prefix() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.prefix
+}
+
+// SetFlags stores the flags, which don't change the output.
+func (l *Logger) SetFlags(flag int) {
+	l.mu.Lock()
+	l.flags = flag
+	l.mu.Unlock()
+}
+
+// Flags returns the flags set with SetFlags() or New().
+func (l *Logger) Flags() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.flags
+}
+
+// Output logs the message with INFO level. The call depth is ignored, as
+// clog finds the caller itself (see clog.SetCaller()).
+func (l *Logger) Output(calldepth int, s string) error {
+	l.log(clog.INFO, strings.TrimSuffix(s, "\n"))
+	return nil
+}
+
+// Print logs the message with INFO level, formatted as with fmt.Sprint.
+func (l *Logger) Print(v ...interface{}) {
+	l.log(clog.INFO, fmt.Sprint(v...))
+}
+
+// Printf logs the message with INFO level, formatted as with fmt.Sprintf.
+func (l *Logger) Printf(format string, v ...interface{}) {
+	l.log(clog.INFO, fmt.Sprintf(format, v...))
+}
+
+// Println logs the message with INFO level, formatted as with fmt.Sprintln.
+func (l *Logger) Println(v ...interface{}) {
+	l.log(clog.INFO, sprintln(v))
+}
+
+// Fatal logs the message with FATAL level, formatted as with fmt.Sprint.
+func (l *Logger) Fatal(v ...interface{}) {
+	l.log(clog.FATAL, fmt.Sprint(v...))
+}
+
+// Fatalf logs the message with FATAL level, formatted as with fmt.Sprintf.
+func (l *Logger) Fatalf(format string, v ...interface{}) {
+	l.log(clog.FATAL, fmt.Sprintf(format, v...))
+}
+
+// Fatalln logs the message with FATAL level, formatted as with fmt.Sprintln.
+func (l *Logger) Fatalln(v ...interface{}) {
+	l.log(clog.FATAL, sprintln(v))
+}
+
+// Panic logs the message with PANIC level, formatted as with fmt.Sprint.
+func (l *Logger) Panic(v ...interface{}) {
+	l.log(clog.PANIC, fmt.Sprint(v...))
+}
+
+// Panicf logs the message with PANIC level, formatted as with fmt.Sprintf.
+func (l *Logger) Panicf(format string, v ...interface{}) {
+	l.log(clog.PANIC, fmt.Sprintf(format, v...))
+}
+
+// Panicln logs the message with PANIC level, formatted as with fmt.Sprintln.
+func (l *Logger) Panicln(v ...interface{}) {
+	l.log(clog.PANIC, sprintln(v))
+}
+
+// SetOutput sets the output of the default clog logger.
+func SetOutput(w io.Writer) {
+	std.SetOutput(w)
+}
+
+// Writer returns a writer logging each line written to it with INFO level
+// using the default clog logger.
+func Writer() io.Writer {
+	return std.Writer()
+}
+
+// SetPrefix sets the prefix prepended to each message.
+func SetPrefix(prefix string) {
+	std.SetPrefix(prefix)
+}
+
+// Prefix returns the prefix prepended to each message.
+func Prefix() string {
+	return std.Prefix()
+}
+
+// SetFlags stores the flags, which don't change the output.
+func SetFlags(flag int) {
+	std.SetFlags(flag)
+}
+
+// Flags returns the flags set with SetFlags().
+func Flags() int {
+	return std.Flags()
+}
+
+// Output logs the message with INFO level. The call depth is ignored.
+func Output(calldepth int, s string) error {
+	return std.Output(calldepth, s)
+}
+
+// Print logs the message with INFO level, formatted as with fmt.Sprint.
+func Print(v ...interface{}) {
+	std.log(clog.INFO, fmt.Sprint(v...))
+}
+
+// Printf logs the message with INFO level, formatted as with fmt.Sprintf.
+func Printf(format string, v ...interface{}) {
+	std.log(clog.INFO, fmt.Sprintf(format, v...))
+}
+
+// Println logs the message with INFO level, formatted as with fmt.Sprintln.
+func Println(v ...interface{}) {
+	std.log(clog.INFO, sprintln(v))
+}
+
+// Fatal logs the message with FATAL level, formatted as with fmt.Sprint.
+func Fatal(v ...interface{}) {
+	std.log(clog.FATAL, fmt.Sprint(v...))
+}
+
+// Fatalf logs the message with FATAL level, formatted as with fmt.Sprintf.
+func Fatalf(format string, v ...interface{}) {
+	std.log(clog.FATAL, fmt.Sprintf(format, v...))
+}
+
+// Fatalln logs the message with FATAL level, formatted as with fmt.Sprintln.
+func Fatalln(v ...interface{}) {
+	std.log(clog.FATAL, sprintln(v))
+}
+
+// Panic logs the message with PANIC level, formatted as with fmt.Sprint.
+func Panic(v ...interface{}) {
+	std.log(clog.PANIC, fmt.Sprint(v...))
+}
+
+// Panicf logs the message with PANIC level, formatted as with fmt.Sprintf.
+func Panicf(format string, v ...interface{}) {
+	std.log(clog.PANIC, fmt.Sprintf(format, v...))
+}
+
+// Panicln logs the message with PANIC level, formatted as with fmt.Sprintln.
+func Panicln(v ...interface{}) {
+	std.log(clog.PANIC, sprintln(v))
+}
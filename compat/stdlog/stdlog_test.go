@@ -0,0 +1,71 @@
+package stdlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/senko/clog"
+)
+
+func TestStdlog(t *testing.T) {
+	out := bytes.Buffer{}
+	clog.Scoped(t)
+	clog.Setup(clog.DEBUG, false)
+	SetOutput(&out)
+
+	Print("a", "b")
+	Println("a", "b")
+	Printf("%d items", 3)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	expected := []string{" INFO ab", " INFO a b", " INFO 3 items"}
+	if len(lines) != len(expected) {
+		t.Fatalf("Unexpected output: %s", out.String())
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, expected[i]) {
+			t.Errorf("Expected %q, got %q", expected[i], line)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Panicln() didn't panic")
+		}
+	}()
+	l := Wrap(clog.New(clog.DEBUG, false))
+	l.SetOutput(&out)
+	l.Panicln("the end")
+}
+
+func TestStdlogCompat(t *testing.T) {
+	out := bytes.Buffer{}
+	clog.Scoped(t)
+	clog.Setup(clog.DEBUG, false)
+	SetOutput(&out)
+
+	SetFlags(LstdFlags | Lshortfile)
+	SetPrefix("app: ")
+	defer SetPrefix("")
+	Output(2, "via output\n")
+	Writer().Write([]byte("via writer\n"))
+
+	if Flags() != LstdFlags|Lshortfile || Prefix() != "app: " {
+		t.Errorf("Flags or prefix not stored")
+	}
+
+	l := New(&out, "db: ", LstdFlags)
+	l.Printf("query took %dms", 42)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	expected := []string{" INFO app: via output", " INFO via writer", " INFO db: query took 42ms"}
+	if len(lines) != len(expected) {
+		t.Fatalf("Unexpected output: %s", out.String())
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, expected[i]) {
+			t.Errorf("Expected %q, got %q", expected[i], line)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package clog
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the fields, in addition to any
+// fields already attached to ctx. Messages logged through the entry
+// returned by FromContext() carry the fields, eg. a request ID attached at
+// the top of an HTTP handler.
+func NewContext(ctx context.Context, fields Fields) context.Context {
+	return context.WithValue(ctx, contextKey{}, mergeFields(contextFields(ctx), fields))
+}
+
+// FromContext returns an entry which logs messages with the fields
+// attached to ctx using the default logger. If ctx has no fields, the
+// entry logs messages without fields.
+func FromContext(ctx context.Context) *Entry {
+	return std.FromContext(ctx)
+}
+
+// FromContext returns an entry which logs messages with the fields
+// attached to ctx using this logger.
+func (l *Logger) FromContext(ctx context.Context) *Entry {
+	return &Entry{logger: l, fields: contextFields(ctx)}
+}
+
+// contextFields returns the fields attached to ctx. The returned map must
+// not be modified.
+func contextFields(ctx context.Context) Fields {
+	fields, _ := ctx.Value(contextKey{}).(Fields)
+	return fields
+}
@@ -0,0 +1,26 @@
+package clog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestContextFields(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetTimestampMode(TimestampSequence)
+
+	l.FromContext(context.Background()).Info("no fields")
+
+	ctx := NewContext(context.Background(), Fields{"req": "r1"})
+	inner := NewContext(ctx, Fields{"user": "alice"})
+	l.FromContext(inner).Info("handled")
+	l.FromContext(ctx).WithFields(Fields{"ms": 3}).Info("done")
+
+	expected := "1 INFO no fields\n2 INFO handled req=r1 user=alice\n3 INFO done ms=3 req=r1\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
@@ -0,0 +1,80 @@
+package clog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+type crashRuntime struct {
+	GoVersion  string `json:"go_version"`
+	NumCPU     int    `json:"num_cpu"`
+	Goroutines int    `json:"goroutines"`
+	HeapAlloc  uint64 `json:"heap_alloc"`
+	Sys        uint64 `json:"sys"`
+	NumGC      uint32 `json:"num_gc"`
+}
+
+type crashReport struct {
+	Time    time.Time        `json:"time"`
+	Level   string           `json:"level"`
+	Message string           `json:"message"`
+	Stack   string           `json:"stack"`
+	Runtime crashRuntime     `json:"runtime"`
+	Build   *debug.BuildInfo `json:"build,omitempty"`
+}
+
+// SetCrashReportDir enables writing a crash report file to the specified
+// directory whenever a message with FATAL or PANIC level is logged. The
+// report is a JSON document with the message, stack trace, runtime
+// statistics and build information. An empty dir disables crash reports.
+func SetCrashReportDir(dir string) {
+	std.SetCrashReportDir(dir)
+}
+
+// SetCrashReportDir enables writing a crash report file to the specified
+// directory whenever a message with FATAL or PANIC level is logged. An
+// empty dir disables crash reports.
+func (l *Logger) SetCrashReportDir(dir string) {
+	l.mu.Lock()
+	l.crashDir = dir
+	l.mu.Unlock()
+}
+
+// writeCrashReport writes the crash report and returns the name of the
+// created file.
+func writeCrashReport(dir string, t time.Time, level LogLevel, msg string) (string, error) {
+	ms := runtime.MemStats{}
+	runtime.ReadMemStats(&ms)
+
+	report := crashReport{
+		Time:    t,
+		Level:   levelNames[level-DEBUG],
+		Message: msg,
+		Stack:   string(debug.Stack()),
+		Runtime: crashRuntime{
+			GoVersion:  runtime.Version(),
+			NumCPU:     runtime.NumCPU(),
+			Goroutines: runtime.NumGoroutine(),
+			HeapAlloc:  ms.HeapAlloc,
+			Sys:        ms.Sys,
+			NumGC:      ms.NumGC,
+		},
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		report.Build = bi
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.Join(dir, fmt.Sprintf("crash-%s-%d.json", t.UTC().Format("20060102T150405.000000000"), os.Getpid()))
+	return name, os.WriteFile(name, data, 0644)
+}
@@ -0,0 +1,64 @@
+package clog
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCrashReport(t *testing.T) {
+	out := bytes.Buffer{}
+	dir := t.TempDir()
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+	SetCrashReportDir(dir)
+	defer SetCrashReportDir("")
+
+	func() {
+		defer func() { recover() }()
+		Log(PANIC, "omg!")
+	}()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "crash-*.json"))
+	if len(files) != 1 {
+		t.Fatalf("Expected one crash report, got %d", len(files))
+	}
+
+	data, _ := os.ReadFile(files[0])
+	report := crashReport{}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Invalid crash report: %s", err)
+	}
+
+	if report.Level != "PANIC" || report.Message != "omg!" {
+		t.Errorf("Incorrect crash report entry: %s %s", report.Level, report.Message)
+	}
+
+	if !strings.Contains(report.Stack, "TestCrashReport") {
+		t.Errorf("Crash report stack trace missing caller: %s", report.Stack)
+	}
+}
+
+func TestCrashReportFailed(t *testing.T) {
+	diag := bytes.Buffer{}
+	file := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(file, nil, 0644)
+
+	l := New(DEBUG, false)
+	l.SetOutput(&bytes.Buffer{})
+	l.SetDiagnosticsOutput(&diag)
+	l.SetCrashReportDir(filepath.Join(file, "crashes"))
+
+	func() {
+		defer func() { recover() }()
+		l.Panic("omg!")
+	}()
+
+	if !strings.Contains(diag.String(), "writing crash report failed: ") {
+		t.Errorf("Crash report failure not reported: %s", diag.String())
+	}
+}
@@ -0,0 +1,95 @@
+package clog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+)
+
+// SetDiagnosticsOutput sets the writer to which the logger reports its own
+// problems, such as messages that failed to format. Diagnostics are
+// disabled if output is nil, which is the default.
+func SetDiagnosticsOutput(output io.Writer) {
+	std.SetDiagnosticsOutput(output)
+}
+
+// SetDiagnosticsOutput sets the writer to which the logger reports its own
+// problems. Diagnostics are disabled if output is nil, which is the default.
+func (l *Logger) SetDiagnosticsOutput(output io.Writer) {
+	l.mu.Lock()
+	l.diag = output
+	l.mu.Unlock()
+}
+
+func (l *Logger) diagf(f string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.diagfLocked(f, args...)
+}
+
+// diagfLocked is like diagf, but must be called with the lock held.
+func (l *Logger) diagfLocked(f string, args ...interface{}) {
+	if l.diag == nil {
+		return
+	}
+
+	fmt.Fprintf(l.diag, "%s clog: %s\n", formatTimestamp(l.clockLocked().Now(), l.timeFormat()), fmt.Sprintf(f, args...))
+}
+
+// DumpDiagnostics logs a diagnostics bundle with the specified level: the
+// logger configuration, runtime statistics and stack traces of all
+// goroutines.
+func DumpDiagnostics(level LogLevel) {
+	std.DumpDiagnostics(level)
+}
+
+// DumpDiagnostics logs a diagnostics bundle with the specified level: the
+// logger configuration, runtime statistics and stack traces of all
+// goroutines.
+func (l *Logger) DumpDiagnostics(level LogLevel) {
+	l.mu.Lock()
+	config := fmt.Sprintf("level=%s color=%s timestamp=%d output=%T crash_dir=%q",
+		levelName(l.level), colorModeName(l.colorMode()), l.timestamp, l.output, l.crashDir)
+	l.mu.Unlock()
+
+	l.Logf(level, "diagnostics: config %s", config)
+
+	ms := runtime.MemStats{}
+	runtime.ReadMemStats(&ms)
+	l.Logf(level, "diagnostics: runtime go=%s cpus=%d goroutines=%d heap_alloc=%d sys=%d num_gc=%d",
+		runtime.Version(), runtime.NumCPU(), runtime.NumGoroutine(), ms.HeapAlloc, ms.Sys, ms.NumGC)
+
+	l.DumpGoroutines(level)
+}
+
+// HandleDiagnosticsSignal installs a handler which calls DumpDiagnostics
+// with the specified level whenever one of the signals (eg. syscall.SIGUSR2)
+// is received. The process is not otherwise affected. The returned function
+// removes the handler, waiting for a dump in progress to finish.
+func HandleDiagnosticsSignal(level LogLevel, sigs ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ch:
+				DumpDiagnostics(level)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+		<-stopped
+	}
+}
@@ -0,0 +1,66 @@
+package clog
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDumpDiagnostics(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(WARNING, false)
+	SetOutput(&out)
+
+	DumpDiagnostics(ERROR)
+
+	for _, part := range []string{
+		" ERROR diagnostics: config level=WARNING color=false",
+		" ERROR diagnostics: runtime go=",
+		" ERROR goroutine dump 1/",
+	} {
+		if !strings.Contains(out.String(), part) {
+			t.Errorf("Diagnostics dump missing %q", part)
+		}
+	}
+}
+
+type notifyBuffer struct {
+	bytes.Buffer
+	written chan struct{}
+}
+
+func (b *notifyBuffer) Write(p []byte) (int, error) {
+	select {
+	case b.written <- struct{}{}:
+	default:
+	}
+	return b.Buffer.Write(p)
+}
+
+func TestHandleDiagnosticsSignal(t *testing.T) {
+	out := notifyBuffer{written: make(chan struct{}, 1)}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+
+	stop := HandleDiagnosticsSignal(INFO, os.Interrupt)
+
+	p, _ := os.FindProcess(os.Getpid())
+	if err := p.Signal(os.Interrupt); err != nil {
+		stop()
+		t.Skipf("Can't send signal: %s", err)
+	}
+
+	select {
+	case <-out.written:
+	case <-time.After(5 * time.Second):
+	}
+	stop()
+
+	if !strings.Contains(out.String(), " INFO diagnostics: config") {
+		t.Errorf("Diagnostics not logged on signal: %s", out.String())
+	}
+}
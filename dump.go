@@ -0,0 +1,49 @@
+package clog
+
+import (
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+func allStacks() string {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// DumpGoroutines logs the stack traces of all goroutines with the specified
+// level. Each goroutine is logged as a separate message, numbered so that
+// the parts of a single dump can be told apart.
+func DumpGoroutines(level LogLevel) {
+	std.DumpGoroutines(level)
+}
+
+// DumpGoroutines logs the stack traces of all goroutines with the specified
+// level, one message per goroutine.
+func (l *Logger) DumpGoroutines(level LogLevel) {
+	stacks := strings.Split(strings.TrimSpace(allStacks()), "\n\n")
+	for idx, stack := range stacks {
+		l.Logf(level, "goroutine dump %d/%d:\n%s", idx+1, len(stacks), stack)
+	}
+}
+
+// DumpGoroutinesHandler returns a http.Handler which calls DumpGoroutines
+// with the specified level on each POST request.
+func DumpGoroutinesHandler(level LogLevel) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		DumpGoroutines(level)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,51 @@
+package clog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDumpGoroutines(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+
+	block := make(chan struct{})
+	defer close(block)
+	go func() { <-block }()
+
+	DumpGoroutines(WARNING)
+
+	if !strings.Contains(out.String(), " WARNING goroutine dump 1/") {
+		t.Errorf("Goroutine dump not logged correctly: %s", out.String())
+	}
+
+	if !strings.Contains(out.String(), "TestDumpGoroutines") {
+		t.Errorf("Goroutine dump missing stack traces: %s", out.String())
+	}
+}
+
+func TestDumpGoroutinesHandler(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+
+	h := DumpGoroutinesHandler(INFO)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed || out.Len() != 0 {
+		t.Errorf("GET request shouldn't trigger a dump")
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	if rec.Code != http.StatusNoContent || !strings.Contains(out.String(), " INFO goroutine dump") {
+		t.Errorf("POST request didn't trigger a dump")
+	}
+}
@@ -0,0 +1,30 @@
+// Command cli is an example command line tool taking the log level from a
+// flag and using color when run in a terminal:
+//
+//	go run ./examples/cli -level debug file1 file2
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/senko/clog"
+)
+
+func main() {
+	level := clog.WARNING
+	flag.TextVar(&level, "level", level, "log level (debug, info, warning, error)")
+	flag.Parse()
+
+	clog.Setup(level, false)
+	clog.SetColorMode(clog.ColorAuto)
+
+	for _, name := range flag.Args() {
+		fi, err := os.Stat(name)
+		if err != nil {
+			clog.Errorf("can't stat %s: %s", name, err)
+			continue
+		}
+		clog.DebugKV("stat", "name", name, "size", fi.Size())
+	}
+}
@@ -0,0 +1,37 @@
+// Command httpservice is an example HTTP service logging each request, with
+// admin endpoints for changing the log level and dumping goroutines:
+//
+//	go run ./examples/httpservice
+//	curl http://localhost:8080/hello
+//	curl -X PUT -H "Authorization: Bearer secret" -d debug http://localhost:8080/debug/loglevel
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/senko/clog"
+)
+
+var log = clog.Named("http")
+
+func main() {
+	clog.SetupFromEnv()
+	clog.SetFormat(clog.FormatLogfmt)
+
+	admin := http.NewServeMux()
+	admin.Handle("/debug/loglevel", clog.LevelHandler())
+	admin.Handle("/debug/goroutines", clog.DumpGoroutinesHandler(clog.INFO))
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/", clog.RequireToken("secret", admin))
+	mux.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		log.Debug("saying hello")
+		fmt.Fprintln(w, "hello")
+	})
+
+	clog.InfoKV("listening", "addr", ":8080")
+	if err := http.ListenAndServe(":8080", clog.HTTPMiddleware(clog.RecoverMiddleware(mux))); err != nil {
+		clog.Fatalf("can't listen: %s", err)
+	}
+}
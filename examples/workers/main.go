@@ -0,0 +1,36 @@
+// Command workers is an example worker pool. Each worker logs through its
+// own named logger, and panics in workers are logged with a stack trace:
+//
+//	LOG_LEVEL=INFO,worker=DEBUG go run ./examples/workers
+package main
+
+import (
+	"github.com/senko/clog"
+)
+
+const numJobs = 20
+
+func main() {
+	clog.SetupFromEnv()
+	clog.SetCaller(true, false)
+
+	jobs := make(chan int)
+	workers := clog.Group{}
+
+	for i := 0; i < 4; i++ {
+		log := clog.Named("worker").WithFields(clog.Fields{"id": i})
+		workers.Go(func() {
+			for job := range jobs {
+				log.Debugf("processing job %d", job)
+			}
+		})
+	}
+
+	for job := 0; job < numJobs; job++ {
+		jobs <- job
+	}
+	close(jobs)
+	workers.Wait()
+
+	clog.Infof("all %d jobs done", numJobs)
+}
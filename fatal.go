@@ -0,0 +1,51 @@
+package clog
+
+import (
+	"os"
+	"sync"
+)
+
+var exitMu sync.Mutex
+var exitFunc = os.Exit
+var exitHandlers []func()
+
+// SetExitFunc sets the function called to exit the process after a message
+// with FATAL level is logged. By default this is os.Exit; tests can replace
+// it to check for fatal errors without exiting. If fn is nil, os.Exit is
+// used.
+func SetExitFunc(fn func(code int)) {
+	if fn == nil {
+		fn = os.Exit
+	}
+
+	exitMu.Lock()
+	exitFunc = fn
+	exitMu.Unlock()
+}
+
+// RegisterExitHandler registers a function to be called after a message
+// with FATAL level is logged, just before the process exits. The handlers
+// are called in the order they were registered.
+func RegisterExitHandler(handler func()) {
+	exitMu.Lock()
+	exitHandlers = append(exitHandlers, handler)
+	exitMu.Unlock()
+}
+
+// fatalExit runs the exit handlers and exits with status 1. A handler
+// panicking doesn't prevent the remaining handlers from running.
+func fatalExit() {
+	exitMu.Lock()
+	handlers := append([]func(){}, exitHandlers...)
+	exit := exitFunc
+	exitMu.Unlock()
+
+	for _, handler := range handlers {
+		func() {
+			defer func() { recover() }()
+			handler()
+		}()
+	}
+
+	exit(1)
+}
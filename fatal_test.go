@@ -0,0 +1,56 @@
+package clog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFatal(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+
+	calls := []string{}
+	SetExitFunc(func(code int) {
+		calls = append(calls, "exit")
+		if code != 1 {
+			t.Errorf("Expected exit code 1, got %d", code)
+		}
+	})
+	defer SetExitFunc(nil)
+
+	RegisterExitHandler(func() { calls = append(calls, "first") })
+	RegisterExitHandler(func() { panic("oops") })
+	RegisterExitHandler(func() { calls = append(calls, "second") })
+	defer func() { exitHandlers = nil }()
+
+	Fatalf("can't open %s", "config")
+
+	if !strings.HasSuffix(out.String(), " FATAL can't open config\n") {
+		t.Errorf("Fatal message not logged correctly: %s", out.String())
+	}
+
+	if strings.Join(calls, ",") != "first,second,exit" {
+		t.Errorf("Incorrect exit sequence: %v", calls)
+	}
+}
+
+func TestFatalFiltered(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(PANIC, false)
+	l.SetOutput(&out)
+
+	exited := false
+	SetExitFunc(func(code int) { exited = true })
+	defer SetExitFunc(nil)
+
+	l.Fatal("filtered")
+	if !exited {
+		t.Errorf("Filtered FATAL message didn't exit")
+	}
+	if out.Len() != 0 {
+		t.Errorf("Filtered FATAL message logged: %s", out.String())
+	}
+}
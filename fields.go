@@ -0,0 +1,257 @@
+package clog
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fields holds structured key/value data attached to a log message.
+type Fields map[string]interface{}
+
+// Entry is a set of fields bound to a logger. Messages logged through the
+// entry carry the fields, which are shown after the message, sorted by key.
+type Entry struct {
+	logger *Logger
+	name   string
+	fields Fields
+}
+
+// WithFields returns an entry which logs messages with the specified fields
+// using the default logger.
+func WithFields(fields Fields) *Entry {
+	return std.WithFields(fields)
+}
+
+// WithFields returns an entry which logs messages with the specified fields
+// using this logger.
+func (l *Logger) WithFields(fields Fields) *Entry {
+	return &Entry{logger: l, fields: mergeFields(nil, fields)}
+}
+
+// WithFields returns a new entry with both the entry's fields and the
+// specified ones. If a key is set in both, the new value is used.
+func (e *Entry) WithFields(fields Fields) *Entry {
+	return &Entry{logger: e.logger, name: e.name, fields: mergeFields(e.fields, fields)}
+}
+
+// withField returns a copy of the fields with the key set to value. A field
+// already using the key is kept with a "fields." prefix.
+func withField(fields Fields, key string, value interface{}) Fields {
+	merged := mergeFields(nil, fields)
+	if v, ok := merged[key]; ok {
+		merged["fields."+key] = v
+	}
+	merged[key] = value
+	return merged
+}
+
+func mergeFields(parent, fields Fields) Fields {
+	merged := make(Fields, len(parent)+len(fields))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged
+}
+
+// kvFields converts alternating keys and values to Fields. A key without a
+// value gets the value "(MISSING)". Keys which aren't strings are converted
+// with fmt.Sprint.
+func kvFields(keyvals []interface{}) Fields {
+	fields := make(Fields, (len(keyvals)+1)/2)
+
+	for idx := 0; idx < len(keyvals); idx += 2 {
+		key, ok := keyvals[idx].(string)
+		if !ok {
+			key = safeValue("%v", keyvals[idx])
+		}
+
+		if idx+1 < len(keyvals) {
+			fields[key] = keyvals[idx+1]
+		} else {
+			fields[key] = "(MISSING)"
+		}
+	}
+
+	return fields
+}
+
+// formatFields renders the fields as space-separated key=value pairs,
+// sorted by key. Values are quoted if needed.
+func formatFields(fields Fields) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b := strings.Builder{}
+	for _, k := range keys {
+		b.WriteByte(' ')
+		b.WriteString(quoteIfNeeded(k))
+		b.WriteByte('=')
+		b.WriteString(quoteIfNeeded(safeValue("%v", fields[k])))
+	}
+
+	return b.String()
+}
+
+func quoteIfNeeded(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\\") ||
+		strings.IndexFunc(s, func(r rune) bool { return !strconv.IsPrint(r) }) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// Log logs a message with the specified log level and the entry's fields.
+func (e *Entry) Log(level LogLevel, msg string) {
+	e.logger.log(e.name, level, msg, e.fields)
+}
+
+// Logf logs a formatted message with the specified log level and the
+// entry's fields.
+func (e *Entry) Logf(level LogLevel, f string, args ...interface{}) {
+	e.logger.log(e.name, level, e.logger.sprintf(f, args...), e.fields)
+}
+
+// Debug is a convenience method equivalent to Log(DEBUG, msg)
+func (e *Entry) Debug(msg string) {
+	e.Log(DEBUG, msg)
+}
+
+// Info is a convenience method equivalent to Log(INFO, msg)
+func (e *Entry) Info(msg string) {
+	e.Log(INFO, msg)
+}
+
+// Warning is a convenience method equivalent to Log(WARNING, msg)
+func (e *Entry) Warning(msg string) {
+	e.Log(WARNING, msg)
+}
+
+// Error is a convenience method equivalent to Log(ERROR, msg)
+func (e *Entry) Error(msg string) {
+	e.Log(ERROR, msg)
+}
+
+// Fatal is a convenience method equivalent to Log(FATAL, msg)
+func (e *Entry) Fatal(msg string) {
+	e.Log(FATAL, msg)
+}
+
+// Panic is a convenience method equivalent to Log(PANIC, msg)
+func (e *Entry) Panic(msg string) {
+	e.Log(PANIC, msg)
+}
+
+// Debugf is a convenience method equivalent to Logf(DEBUG, fmt, args...)
+func (e *Entry) Debugf(f string, args ...interface{}) {
+	e.Logf(DEBUG, f, args...)
+}
+
+// Infof is a convenience method equivalent to Logf(INFO, fmt, args...)
+func (e *Entry) Infof(f string, args ...interface{}) {
+	e.Logf(INFO, f, args...)
+}
+
+// Warningf is a convenience method equivalent to Logf(WARNING, fmt, args...)
+func (e *Entry) Warningf(f string, args ...interface{}) {
+	e.Logf(WARNING, f, args...)
+}
+
+// Errorf is a convenience method equivalent to Logf(ERROR, fmt, args...)
+func (e *Entry) Errorf(f string, args ...interface{}) {
+	e.Logf(ERROR, f, args...)
+}
+
+// Fatalf is a convenience method equivalent to Logf(FATAL, fmt, args...)
+func (e *Entry) Fatalf(f string, args ...interface{}) {
+	e.Logf(FATAL, f, args...)
+}
+
+// Panicf is a convenience method equivalent to Logf(PANIC, fmt, args...)
+func (e *Entry) Panicf(f string, args ...interface{}) {
+	e.Logf(PANIC, f, args...)
+}
+
+// LogKV logs a message with the specified log level and fields given as
+// alternating keys and values.
+func (l *Logger) LogKV(level LogLevel, msg string, keyvals ...interface{}) {
+	l.log("", level, msg, kvFields(keyvals))
+}
+
+// DebugKV is a convenience method equivalent to LogKV(DEBUG, msg, keyvals...)
+func (l *Logger) DebugKV(msg string, keyvals ...interface{}) {
+	l.LogKV(DEBUG, msg, keyvals...)
+}
+
+// InfoKV is a convenience method equivalent to LogKV(INFO, msg, keyvals...)
+func (l *Logger) InfoKV(msg string, keyvals ...interface{}) {
+	l.LogKV(INFO, msg, keyvals...)
+}
+
+// WarningKV is a convenience method equivalent to LogKV(WARNING, msg, keyvals...)
+func (l *Logger) WarningKV(msg string, keyvals ...interface{}) {
+	l.LogKV(WARNING, msg, keyvals...)
+}
+
+// ErrorKV is a convenience method equivalent to LogKV(ERROR, msg, keyvals...)
+func (l *Logger) ErrorKV(msg string, keyvals ...interface{}) {
+	l.LogKV(ERROR, msg, keyvals...)
+}
+
+// FatalKV is a convenience method equivalent to LogKV(FATAL, msg, keyvals...)
+func (l *Logger) FatalKV(msg string, keyvals ...interface{}) {
+	l.LogKV(FATAL, msg, keyvals...)
+}
+
+// PanicKV is a convenience method equivalent to LogKV(PANIC, msg, keyvals...)
+func (l *Logger) PanicKV(msg string, keyvals ...interface{}) {
+	l.LogKV(PANIC, msg, keyvals...)
+}
+
+// LogKV logs a message with the specified log level and fields given as
+// alternating keys and values, for example:
+//
+//	clog.LogKV(clog.INFO, "login ok", "user", id, "req", reqID)
+func LogKV(level LogLevel, msg string, keyvals ...interface{}) {
+	std.LogKV(level, msg, keyvals...)
+}
+
+// DebugKV is a convenience function equivalent to LogKV(DEBUG, msg, keyvals...)
+func DebugKV(msg string, keyvals ...interface{}) {
+	std.LogKV(DEBUG, msg, keyvals...)
+}
+
+// InfoKV is a convenience function equivalent to LogKV(INFO, msg, keyvals...)
+func InfoKV(msg string, keyvals ...interface{}) {
+	std.LogKV(INFO, msg, keyvals...)
+}
+
+// WarningKV is a convenience function equivalent to LogKV(WARNING, msg, keyvals...)
+func WarningKV(msg string, keyvals ...interface{}) {
+	std.LogKV(WARNING, msg, keyvals...)
+}
+
+// ErrorKV is a convenience function equivalent to LogKV(ERROR, msg, keyvals...)
+func ErrorKV(msg string, keyvals ...interface{}) {
+	std.LogKV(ERROR, msg, keyvals...)
+}
+
+// FatalKV is a convenience function equivalent to LogKV(FATAL, msg, keyvals...)
+func FatalKV(msg string, keyvals ...interface{}) {
+	std.LogKV(FATAL, msg, keyvals...)
+}
+
+// PanicKV is a convenience function equivalent to LogKV(PANIC, msg, keyvals...)
+func PanicKV(msg string, keyvals ...interface{}) {
+	std.LogKV(PANIC, msg, keyvals...)
+}
@@ -0,0 +1,39 @@
+package clog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWithFields(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+
+	e := WithFields(Fields{"user": 42, "req": "abc"})
+	e.WithFields(Fields{"req": "def", "msg": "hello world"}).Info("login ok")
+	e.Warningf("retry %d", 3)
+
+	lines := strings.Split(out.String(), "\n")
+	if !strings.HasSuffix(lines[0], ` INFO login ok msg="hello world" req=def user=42`) {
+		t.Errorf("Incorrect fields output: %s", lines[0])
+	}
+
+	if !strings.HasSuffix(lines[1], ` WARNING retry 3 req=abc user=42`) {
+		t.Errorf("Parent entry fields changed: %s", lines[1])
+	}
+}
+
+func TestLogKV(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+
+	InfoKV("login ok", "user", 42, "empty", "", 7, "x", "dangling")
+	if !strings.HasSuffix(out.String(), ` INFO login ok 7=x dangling=(MISSING) empty="" user=42`+"\n") {
+		t.Errorf("Incorrect key/value output: %s", out.String())
+	}
+}
@@ -0,0 +1,79 @@
+package clog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+type fifo struct {
+	mu   sync.Mutex
+	name string
+	f    *os.File
+}
+
+// OpenFIFO opens a named pipe (FIFO) for writing log messages, for feeding
+// them to another process. Unlike opening the pipe directly, this doesn't
+// block until a reader connects. Messages written while no reader is
+// attached are dropped, and the pipe is reopened once a reader appears,
+// also after a reader disconnects.
+func OpenFIFO(name string) (io.WriteCloser, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if fi.Mode()&os.ModeNamedPipe == 0 {
+		return nil, fmt.Errorf("clog: %s is not a named pipe", name)
+	}
+
+	p := &fifo{name: name}
+	if err := p.open(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *fifo) open() error {
+	f, err := openFIFO(p.name)
+	if err != nil {
+		return err
+	}
+	p.f = f
+	return nil
+}
+
+func (p *fifo) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.f == nil {
+		if err := p.open(); err != nil {
+			return 0, err
+		}
+		if p.f == nil {
+			return len(b), nil
+		}
+	}
+
+	n, err := p.f.Write(b)
+	if err != nil && isPipeClosed(err) {
+		p.f.Close()
+		p.f = nil
+		return len(b), nil
+	}
+	return n, err
+}
+
+// Close closes the pipe.
+func (p *fifo) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.f == nil {
+		return nil
+	}
+	err := p.f.Close()
+	p.f = nil
+	return err
+}
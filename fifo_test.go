@@ -0,0 +1,62 @@
+//go:build unix && !illumos && !solaris && !aix
+
+package clog
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestOpenFIFO(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log.fifo")
+	if err := syscall.Mkfifo(name, 0600); err != nil {
+		t.Skipf("Can't create FIFO: %s", err)
+	}
+
+	w, err := OpenFIFO(name)
+	if err != nil {
+		t.Fatalf("Can't open FIFO: %s", err)
+	}
+	defer w.Close()
+
+	l := New(DEBUG, false)
+	l.SetOutput(w)
+	l.SetTimestampMode(TimestampEpoch)
+	l.SetClock(fixedClock(time.Unix(0, 0)))
+	l.Info("nobody listening")
+
+	r, err := os.OpenFile(name, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		t.Fatalf("Can't open FIFO for reading: %s", err)
+	}
+
+	l.Info("hello")
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Can't read from FIFO: %s", err)
+	}
+	if line != "0 INFO hello\n" {
+		t.Errorf("Unexpected message read from FIFO: %q", line)
+	}
+
+	r.Close()
+	if _, err := w.Write([]byte("reader gone\n")); err != nil {
+		t.Errorf("Write failed after reader disconnected: %s", err)
+	}
+	if _, err := w.Write([]byte("still gone\n")); err != nil {
+		t.Errorf("Write failed without a reader: %s", err)
+	}
+}
+
+func TestOpenFIFONotPipe(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(name, nil, 0644)
+
+	if _, err := OpenFIFO(name); err == nil {
+		t.Errorf("Expected error opening a regular file as FIFO")
+	}
+}
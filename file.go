@@ -0,0 +1,48 @@
+package clog
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+type sharedFile struct {
+	mu   sync.Mutex
+	f    *os.File
+	lock bool
+}
+
+// OpenSharedFile opens the file for appending log messages, creating it if
+// needed, so that several processes (eg. preforked workers) can log to the
+// same file. The file is opened with O_APPEND and each message is written
+// with a single write, so lines from different processes don't overwrite
+// each other. If lock is set, an advisory lock is also held on the file
+// during each write (on systems supporting it), so that long lines can't be
+// interleaved either.
+func OpenSharedFile(name string, lock bool) (io.WriteCloser, error) {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sharedFile{f: f, lock: lock}, nil
+}
+
+func (s *sharedFile) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lock {
+		if err := lockFile(s.f); err != nil {
+			return 0, err
+		}
+		defer unlockFile(s.f)
+	}
+
+	return s.f.Write(p)
+}
+
+// Close closes the file.
+func (s *sharedFile) Close() error {
+	return s.f.Close()
+}
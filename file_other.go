@@ -0,0 +1,27 @@
+//go:build !unix || solaris || aix
+
+package clog
+
+import (
+	"os"
+)
+
+// Advisory locks aren't supported here; O_APPEND writes are relied on.
+
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
+
+// Named pipes can't be opened without blocking here.
+
+func openFIFO(name string) (*os.File, error) {
+	return os.OpenFile(name, os.O_WRONLY, 0)
+}
+
+func isPipeClosed(err error) bool {
+	return false
+}
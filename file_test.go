@@ -0,0 +1,48 @@
+package clog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestOpenSharedFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(name, []byte("existing line\n"), 0644)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 2; i++ {
+		f, err := OpenSharedFile(name, i == 1)
+		if err != nil {
+			t.Fatalf("Can't open shared file: %s", err)
+		}
+		defer f.Close()
+
+		l := New(DEBUG, false)
+		l.SetOutput(f)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Info(strings.Repeat("x", 1000))
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, _ := os.ReadFile(name)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 201 || lines[0] != "existing line" {
+		t.Fatalf("Expected 201 lines starting with the existing one, got %d", len(lines))
+	}
+
+	for _, line := range lines[1:] {
+		if !strings.HasSuffix(line, " INFO "+strings.Repeat("x", 1000)) {
+			t.Errorf("Corrupted line: %q", line)
+			break
+		}
+	}
+}
@@ -0,0 +1,31 @@
+//go:build unix && !solaris && !aix
+
+package clog
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+}
+
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
+
+// openFIFO opens the pipe without waiting for a reader, returning a nil
+// file if there is none.
+func openFIFO(name string) (*os.File, error) {
+	f, err := os.OpenFile(name, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if errors.Is(err, syscall.ENXIO) {
+		return nil, nil
+	}
+	return f, err
+}
+
+func isPipeClosed(err error) bool {
+	return errors.Is(err, syscall.EPIPE)
+}
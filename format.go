@@ -0,0 +1,122 @@
+package clog
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Format int
+
+// Available output formats
+const (
+	FormatText Format = iota
+	FormatJSON
+	FormatECS
+	FormatLogfmt
+	FormatGCP
+	FormatCloudWatch
+
+	// FormatColumns is the text format, laid out in two columns for
+	// reading in a terminal: the timestamp and level on the left, and the
+	// message wrapped to the terminal width on the right.
+	FormatColumns
+)
+
+// formatNames maps the names accepted in LOG_FORMAT to the formats.
+var formatNames = map[string]Format{
+	"text":       FormatText,
+	"json":       FormatJSON,
+	"ecs":        FormatECS,
+	"logfmt":     FormatLogfmt,
+	"gcp":        FormatGCP,
+	"cloudwatch": FormatCloudWatch,
+	"columns":    FormatColumns,
+}
+
+// SetFormat sets the output format of the logger.
+func SetFormat(format Format) {
+	std.SetFormat(format)
+}
+
+// SetFormat sets the output format of the logger. In JSON format, each
+// message is a single JSON object with the time, level and msg keys and
+// the message fields. FormatECS is also JSON, but uses Elastic Common
+// Schema field names (@timestamp, log.level, message, trace.id,
+// service.name, error.stack_trace, log.logger, log.origin.file.name, ...).
+// FormatGCP uses the severity and message keys expected by Google Cloud
+// Logging, and FormatCloudWatch the timestamp, level and msg keys
+// conventional in CloudWatch Logs Insights.
+// FormatLogfmt writes the time, level, msg and fields as key=value pairs.
+// Outside the text formats, the logger name and caller are added as the
+// logger and caller fields, and fields already using those keys are kept
+// with a "fields." prefix. Colors are only used in the text and columns
+// formats. The columns format wraps messages at the width given by the
+// COLUMNS environment variable (80 if not set).
+func (l *Logger) SetFormat(format Format) {
+	l.mu.Lock()
+	l.format = format
+	l.mu.Unlock()
+}
+
+// sprintf formats the message like fmt.Sprintf, but never panics. If
+// formatting fails, the failure is reported to the diagnostics output and
+// the format string and raw argument values are returned instead.
+func (l *Logger) sprintf(f string, args ...interface{}) (msg string) {
+	defer func() {
+		if p := recover(); p != nil {
+			l.diagf("formatting %q panicked: %s", f, safeValue("%v", p))
+			msg = f + rawArgs(args)
+		}
+	}()
+
+	msg = fmt.Sprintf(f, args...)
+	if strings.Contains(msg, "(PANIC=") {
+		l.diagf("formatting %q: argument panicked while being formatted", f)
+	} else if l.isStrictFormat() && strings.Contains(msg, "%!") {
+		l.diagf("formatting %q: bad verb or argument count: %s", f, msg)
+	}
+
+	return msg
+}
+
+// SetStrictFormat enables checking formatted messages for signs of format
+// string mistakes, like %!s(MISSING) or %!(EXTRA ...). Mistakes are
+// reported to the diagnostics output.
+func SetStrictFormat(strict bool) {
+	std.SetStrictFormat(strict)
+}
+
+// SetStrictFormat enables checking formatted messages for signs of format
+// string mistakes, reporting them to the diagnostics output.
+func (l *Logger) SetStrictFormat(strict bool) {
+	l.mu.Lock()
+	l.strictFormat = strict
+	l.mu.Unlock()
+}
+
+func (l *Logger) isStrictFormat() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.strictFormat
+}
+
+// safeValue formats a single value, falling back to its type name if that
+// panics.
+func safeValue(verb string, v interface{}) (s string) {
+	defer func() {
+		if recover() != nil {
+			s = fmt.Sprintf("%T", v)
+		}
+	}()
+
+	return fmt.Sprintf(verb, v)
+}
+
+func rawArgs(args []interface{}) string {
+	parts := make([]string, len(args))
+	for idx, arg := range args {
+		parts[idx] = safeValue("%#v", arg)
+	}
+
+	return " [" + strings.Join(parts, ", ") + "]"
+}
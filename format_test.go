@@ -0,0 +1,76 @@
+package clog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type evilStringer struct{}
+
+func (evilStringer) String() string {
+	panic(evilStringer{})
+}
+
+type panickyStringer struct{}
+
+func (panickyStringer) String() string {
+	panic("boom")
+}
+
+func TestSafeSprintf(t *testing.T) {
+	out := bytes.Buffer{}
+	diag := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+	SetDiagnosticsOutput(&diag)
+	defer SetDiagnosticsOutput(nil)
+
+	Logf(INFO, "value: %s", evilStringer{})
+	if !strings.Contains(out.String(), " INFO value: %s [clog.evilStringer{}]") {
+		t.Errorf("Raw values not logged: %s", out.String())
+	}
+
+	if !strings.Contains(diag.String(), `clog: formatting "value: %s" panicked: clog.evilStringer`) {
+		t.Errorf("Formatting failure not reported: %s", diag.String())
+	}
+
+	diag.Reset()
+	Logf(INFO, "value: %s", panickyStringer{})
+	if !strings.Contains(diag.String(), `clog: formatting "value: %s": argument panicked`) {
+		t.Errorf("Panicking argument not reported: %s", diag.String())
+	}
+}
+
+func TestStrictFormat(t *testing.T) {
+	out := bytes.Buffer{}
+	diag := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+	SetDiagnosticsOutput(&diag)
+	defer SetDiagnosticsOutput(nil)
+
+	// kept in a variable so vet doesn't flag the deliberate mistake
+	f := "%s and %s"
+
+	Logf(INFO, f, "one")
+	if diag.Len() != 0 {
+		t.Errorf("Format mistakes reported without strict mode: %s", diag.String())
+	}
+
+	SetStrictFormat(true)
+	defer SetStrictFormat(false)
+
+	Logf(INFO, f, "one")
+	if !strings.Contains(diag.String(), `clog: formatting "%s and %s": bad verb or argument count: one and %!s(MISSING)`) {
+		t.Errorf("Missing argument not reported: %s", diag.String())
+	}
+
+	diag.Reset()
+	Logf(INFO, "%d%%", 42)
+	if diag.Len() != 0 {
+		t.Errorf("Correct format reported as mistake: %s", diag.String())
+	}
+}
@@ -0,0 +1,156 @@
+package clog
+
+import (
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Formatter renders log records as lines of output, for full control over
+// the output format. The returned line shouldn't end with a newline, which
+// is added by the logger. The record's fields are a copy (see Record).
+type Formatter interface {
+	Format(rec Record) []byte
+}
+
+// FormatterFunc adapts an ordinary function to the Formatter interface.
+type FormatterFunc func(rec Record) []byte
+
+// Format calls f(rec).
+func (f FormatterFunc) Format(rec Record) []byte {
+	return f(rec)
+}
+
+// SetFormatter sets a custom formatter for the default logger, which is
+// used instead of the format set with SetFormat(). If the caller is shown
+// (see SetCaller()), it's passed to the formatter as the "caller" field.
+// Colors and the timestamp settings only apply to the built-in formats and
+// layouts set with SetLayout(). If formatter is nil, the built-in format is
+// used again.
+func SetFormatter(formatter Formatter) {
+	std.SetFormatter(formatter)
+}
+
+// SetFormatter sets a custom formatter for the logger, which is used
+// instead of the format set with SetFormat().
+func (l *Logger) SetFormatter(formatter Formatter) {
+	l.mu.Lock()
+	l.formatter = formatter
+	l.mu.Unlock()
+}
+
+// builtinFormatter returns the formatter for the logger's output format,
+// adjusting the record for it. It must be called with the lock held.
+func (l *Logger) builtinFormatter(output io.Writer, caller string, rec *Record) Formatter {
+	tf := l.recordTime(rec)
+
+	if !l.isText() {
+		if caller != "" {
+			rec.Fields = withField(rec.Fields, "caller", caller)
+		}
+		if rec.Name != "" {
+			rec.Fields = withField(rec.Fields, "logger", rec.Name)
+		}
+	}
+
+	switch l.format {
+	case FormatJSON:
+		return jsonFormatter{defaultSchema, tf}
+	case FormatECS:
+		return jsonFormatter{ecsSchema, tf}
+	case FormatGCP:
+		return jsonFormatter{gcpSchema, tf}
+	case FormatCloudWatch:
+		return jsonFormatter{cloudWatchSchema, tf}
+	case FormatLogfmt:
+		return logfmtFormatter{tf}
+	}
+
+	if _, ok := output.(*syslogWriter); ok {
+		return syslogTextFormatter{caller: caller}
+	}
+
+	f := textFormatter{tf: tf, caller: caller, levelOnly: l.colorLevelOnly}
+	if l.useColor || (l.colorAuto && l.isTerminal(output)) {
+		f.color = colorCodes[rec.Level-DEBUG]
+		if l.colors != nil {
+			f.color = l.colors[rec.Level-DEBUG]
+		}
+		f.colored = true
+	}
+	if l.format == FormatColumns {
+		f.width = terminalWidth()
+	}
+	return f
+}
+
+// recordTime returns the timestamp settings for the record, replacing its
+// time with the message number in the TimestampSequence mode. It must be
+// called with the lock held.
+func (l *Logger) recordTime(rec *Record) timeFormat {
+	tf := l.timeFormat()
+	if tf.mode == TimestampSequence {
+		l.seq++
+		rec.Time, tf = time.Unix(l.seq, 0), timeFormat{mode: TimestampEpoch}
+	}
+	return tf
+}
+
+type jsonFormatter struct {
+	schema jsonSchema
+	tf     timeFormat
+}
+
+func (f jsonFormatter) Format(rec Record) []byte {
+	return []byte(formatJSON(f.schema, rec.Time, f.tf, rec.Level, rec.Msg, rec.Fields))
+}
+
+type logfmtFormatter struct {
+	tf timeFormat
+}
+
+func (f logfmtFormatter) Format(rec Record) []byte {
+	return []byte(formatLogfmt(rec.Time, f.tf, rec.Level, rec.Msg, rec.Fields))
+}
+
+// textFormatter renders the text format, and the columns format if width
+// is set.
+type textFormatter struct {
+	tf        timeFormat
+	caller    string
+	colored   bool
+	color     string
+	levelOnly bool
+	width     int
+}
+
+func (f textFormatter) Format(rec Record) []byte {
+	text := rec.Msg
+	if f.caller != "" {
+		text = f.caller + " " + text
+	}
+	if rec.Name != "" {
+		text = "[" + rec.Name + "] " + text
+	}
+
+	levelTag := levelNames[rec.Level-DEBUG]
+	if f.colored && f.levelOnly && f.color != "" {
+		levelTag = f.color + levelTag + noColor
+	}
+
+	var line string
+	stamp := formatTimestamp(rec.Time, f.tf)
+	if f.width > 0 {
+		pad := strings.Repeat(" ", levelWidth-len(levelNames[rec.Level-DEBUG]))
+		gutterWidth := utf8.RuneCountInString(stamp) + levelWidth + 2
+		line = formatColumns(stamp+" "+levelTag+pad+" ", gutterWidth, text+formatFields(rec.Fields), f.width)
+	} else {
+		line = stamp + " " + levelTag + " " + text + formatFields(rec.Fields)
+	}
+
+	if f.colored && !f.levelOnly {
+		line = f.color + line + noColor
+	}
+	return []byte(line)
+}
@@ -0,0 +1,84 @@
+package clog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetFormatter(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, true)
+	l.SetOutput(&out)
+	l.SetClock(fixedClock(time.Unix(0, 0)))
+	l.SetCaller(true, false)
+	l.SetFormatter(FormatterFunc(func(rec Record) []byte {
+		return []byte(fmt.Sprintf("%d|%s|%s|%s|%v|%v", rec.Time.Unix(), rec.Level, rec.Name, rec.Msg,
+			rec.Fields["id"], rec.Fields["caller"]))
+	}))
+
+	l.Named("db").WithFields(Fields{"id": 1}).Info("query")
+	loc := prevLine()
+
+	if line := out.String(); !strings.HasPrefix(line, "0|INFO|db|query|1|") || !strings.HasSuffix(line, loc+"\n") {
+		t.Errorf("Unexpected output, expected caller %s: %q", loc, line)
+	}
+
+	out.Reset()
+	l.SetCaller(false, false)
+	l.SetTimestampMode(TimestampEpoch)
+	l.SetFormatter(nil)
+	l.Info("plain")
+	if out.String() != colorCodes[INFO-DEBUG]+"0 INFO plain"+noColor+"\n" {
+		t.Errorf("Expected built-in format, got: %q", out.String())
+	}
+}
+
+func TestBuiltinFormatters(t *testing.T) {
+	rec := Record{Level: WARNING, Time: time.Unix(0, 0).UTC(), Msg: "hi", Fields: Fields{"a": 1}}
+	tf := timeFormat{mode: TimestampEpoch}
+
+	tests := []struct {
+		f        Formatter
+		expected string
+	}{
+		{textFormatter{tf: tf}, "0 WARNING hi a=1"},
+		{logfmtFormatter{tf}, `time=0 level=WARNING msg=hi a=1`},
+		{jsonFormatter{defaultSchema, tf}, `{"time":0,"level":"WARNING","msg":"hi","a":1}`},
+	}
+	for _, test := range tests {
+		if line := string(test.f.Format(rec)); line != test.expected {
+			t.Errorf("%T: expected %q, got %q", test.f, test.expected, line)
+		}
+	}
+}
+
+func TestFormatterFieldsCopy(t *testing.T) {
+	l := New(DEBUG, false)
+	l.SetOutput(io.Discard)
+	l.SetFormatter(FormatterFunc(func(rec Record) []byte {
+		rec.Fields["msg"] = rec.Msg
+		return []byte(rec.Msg)
+	}))
+
+	e := l.WithFields(Fields{"shared": true})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				e.Info("message")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(e.fields) != 1 {
+		t.Errorf("Formatter modified the entry's fields: %v", e.fields)
+	}
+}
@@ -0,0 +1,84 @@
+package clog
+
+import (
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+var running int64
+
+// Go runs fn in a new goroutine. If fn panics, the panic is logged with
+// ERROR level, together with the stack trace and the number of goroutines
+// started by Go that are still running, and then re-raised.
+func Go(fn func()) {
+	go watch(fn)
+}
+
+// Running returns the number of goroutines started by Go (or Group.Go)
+// that are still running.
+func Running() int {
+	return int(atomic.LoadInt64(&running))
+}
+
+func watch(fn func()) {
+	atomic.AddInt64(&running, 1)
+
+	defer func() {
+		n := atomic.AddInt64(&running, -1)
+		if p := recover(); p != nil {
+			Errorf("goroutine panic: %v (running: %d)\n%s", p, n, debug.Stack())
+			panic(p)
+		}
+	}()
+
+	fn()
+}
+
+// Recover recovers a panic, logging it with ERROR level together with the
+// stack trace using the default logger. If repanic is true, the panic is
+// re-raised after logging it. It must be called directly by a deferred
+// function:
+//
+//	defer clog.Recover(false)
+func Recover(repanic bool) {
+	if p := recover(); p != nil {
+		std.logPanic(p, repanic)
+	}
+}
+
+// Recover recovers a panic, logging it with ERROR level together with the
+// stack trace using this logger. It must be called directly by a deferred
+// function.
+func (l *Logger) Recover(repanic bool) {
+	if p := recover(); p != nil {
+		l.logPanic(p, repanic)
+	}
+}
+
+func (l *Logger) logPanic(p interface{}, repanic bool) {
+	l.Errorf("panic: %v\n%s", p, debug.Stack())
+	if repanic {
+		panic(p)
+	}
+}
+
+// Group runs a collection of goroutines, logging any of them that panic
+// in the same way as Go. The zero value is ready to use.
+type Group struct {
+	wg sync.WaitGroup
+}
+
+// Go runs fn in a new goroutine belonging to the group.
+func (g *Group) Go(fn func()) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		watch(fn)
+	}()
+}
+
+// Wait blocks until all goroutines in the group have returned.
+func (g *Group) Wait() {
+	g.wg.Wait()
+}
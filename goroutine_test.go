@@ -0,0 +1,71 @@
+package clog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWatchPanic(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("Expected panic to be re-raised, got: %v", p)
+		}
+
+		if !strings.Contains(out.String(), " ERROR goroutine panic: boom (running: 0)") {
+			t.Errorf("Panic not logged correctly: %s", out.String())
+		}
+
+		if !strings.Contains(out.String(), "goroutine_test.go") {
+			t.Errorf("Stack trace not logged: %s", out.String())
+		}
+	}()
+
+	watch(func() { panic("boom") })
+}
+
+func TestGroup(t *testing.T) {
+	g := Group{}
+	done := make(chan struct{}, 3)
+
+	for i := 0; i < 3; i++ {
+		g.Go(func() { done <- struct{}{} })
+	}
+	g.Wait()
+
+	if len(done) != 3 {
+		t.Errorf("Expected 3 goroutines to finish, got %d", len(done))
+	}
+
+	if Running() != 0 {
+		t.Errorf("Expected no running goroutines, got %d", Running())
+	}
+}
+
+func TestRecover(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+
+	func() {
+		defer l.Recover(false)
+		panic("boom")
+	}()
+
+	if !strings.Contains(out.String(), " ERROR panic: boom\n") || !strings.Contains(out.String(), "goroutine_test.go") {
+		t.Errorf("Panic not logged correctly: %s", out.String())
+	}
+
+	defer func() {
+		if p := recover(); p != "again" {
+			t.Errorf("Expected panic to be re-raised, got: %v", p)
+		}
+	}()
+	defer l.Recover(true)
+	panic("again")
+}
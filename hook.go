@@ -0,0 +1,95 @@
+package clog
+
+import (
+	"errors"
+	"time"
+)
+
+// Record is a single log message passed to hooks and formatters. Name is
+// the name of the named logger (see Named()) the message was logged with,
+// if any.
+//
+// Hooks and custom formatters get a private copy of the message fields,
+// which they can modify directly or with SetField() and DeleteField(),
+// without affecting other messages logged with the same Entry.
+type Record struct {
+	Name   string
+	Level  LogLevel
+	Time   time.Time
+	Msg    string
+	Fields Fields
+}
+
+// SetField sets the field in the record.
+func (r *Record) SetField(key string, value interface{}) {
+	if r.Fields == nil {
+		r.Fields = Fields{}
+	}
+	r.Fields[key] = value
+}
+
+// DeleteField removes the field from the record.
+func (r *Record) DeleteField(key string) {
+	delete(r.Fields, key)
+}
+
+// Hook processes log records before they are written. Hooks can forward
+// records elsewhere (eg. to an error tracker or metrics), modify the
+// message and fields in the record, or drop the record by returning
+// ErrDropRecord. Hooks are called for every record at or above the
+// logger's level, so hooks interested only in some levels should check
+// the record's Level. Other errors are reported to the diagnostics output
+// and don't stop the record from being logged.
+type Hook interface {
+	Fire(rec *Record) error
+}
+
+// HookFunc adapts an ordinary function to the Hook interface.
+type HookFunc func(rec *Record) error
+
+// Fire calls f(rec).
+func (f HookFunc) Fire(rec *Record) error {
+	return f(rec)
+}
+
+// ErrDropRecord can be returned by a hook to drop the record. Records with
+// FATAL or PANIC level still exit the process or panic when dropped.
+var ErrDropRecord = errors.New("clog: drop record")
+
+// AddHook adds a hook to the default logger. Hooks are called in the order
+// they were added.
+func AddHook(hook Hook) {
+	std.AddHook(hook)
+}
+
+// AddHook adds a hook to the logger. Hooks are called in the order they
+// were added.
+func (l *Logger) AddHook(hook Hook) {
+	l.mu.Lock()
+	l.hooks = append(l.hooks[:len(l.hooks):len(l.hooks)], hook)
+	l.mu.Unlock()
+}
+
+// fireHooks runs the hooks on the record, updating the message and fields
+// with any changes made by the hooks. It returns false if the record was
+// dropped.
+func (l *Logger) fireHooks(hooks []Hook, name string, level LogLevel, now time.Time, msg *string, fields *Fields) bool {
+	if len(hooks) == 0 {
+		return true
+	}
+
+	rec := &Record{Name: name, Level: level, Time: now, Msg: *msg, Fields: mergeFields(nil, *fields)}
+
+	for _, hook := range hooks {
+		err := hook.Fire(rec)
+		if err == ErrDropRecord {
+			return false
+		}
+		if err != nil {
+			l.diagf("hook %T failed: %s", hook, err)
+		}
+	}
+
+	*msg, *fields = rec.Msg, rec.Fields
+	return true
+}
@@ -0,0 +1,147 @@
+package clog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	out := bytes.Buffer{}
+	diag := bytes.Buffer{}
+	l := New(INFO, false)
+	l.SetOutput(&out)
+	l.SetDiagnosticsOutput(&diag)
+
+	seen := []string{}
+	l.AddHook(HookFunc(func(rec *Record) error {
+		if rec.Level >= ERROR {
+			seen = append(seen, rec.Msg)
+		}
+		return nil
+	}))
+	l.AddHook(HookFunc(func(rec *Record) error {
+		if rec.Fields["secret"] != nil {
+			rec.Fields["secret"] = "***"
+		}
+		if strings.HasPrefix(rec.Msg, "noisy") {
+			return ErrDropRecord
+		}
+		return nil
+	}))
+
+	e := l.WithFields(Fields{"secret": "hunter2"})
+	e.Error("login failed")
+	l.Info("noisy message")
+	l.Debug("below level")
+
+	if !strings.HasSuffix(out.String(), " ERROR login failed secret=***\n") {
+		t.Errorf("Hook changes not applied: %s", out.String())
+	}
+
+	if strings.Contains(out.String(), "noisy") {
+		t.Errorf("Dropped record was logged: %s", out.String())
+	}
+
+	if len(seen) != 1 || seen[0] != "login failed" {
+		t.Errorf("Hook not called correctly: %v", seen)
+	}
+
+	if e.fields["secret"] != "hunter2" {
+		t.Errorf("Hook modified the entry's fields")
+	}
+
+	if diag.Len() != 0 {
+		t.Errorf("Unexpected diagnostics: %s", diag.String())
+	}
+}
+
+func TestHookError(t *testing.T) {
+	out := bytes.Buffer{}
+	diag := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetDiagnosticsOutput(&diag)
+
+	l.AddHook(HookFunc(func(rec *Record) error {
+		return errors.New("sentry unreachable")
+	}))
+	l.Info("hello")
+
+	if !strings.HasSuffix(out.String(), " INFO hello\n") {
+		t.Errorf("Record not logged after hook error: %s", out.String())
+	}
+
+	if !strings.Contains(diag.String(), "clog: hook clog.HookFunc failed: sentry unreachable") {
+		t.Errorf("Hook error not reported: %s", diag.String())
+	}
+}
+
+func TestRecordCopyOnWrite(t *testing.T) {
+	l := New(DEBUG, false)
+	l.SetOutput(io.Discard)
+
+	seen := make(chan bool, 800)
+	l.AddHook(HookFunc(func(rec *Record) error {
+		rec.SetField("worker", rec.Msg)
+		rec.DeleteField("shared")
+		return nil
+	}))
+	l.AddHook(HookFunc(func(rec *Record) error {
+		_, shared := rec.Fields["shared"]
+		seen <- rec.Fields["worker"] == rec.Msg && !shared
+		return nil
+	}))
+
+	e := l.WithFields(Fields{"shared": true})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(msg string) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				e.Named("worker").Info(msg)
+			}
+		}(string(rune('a' + i)))
+	}
+	wg.Wait()
+	close(seen)
+
+	for ok := range seen {
+		if !ok {
+			t.Fatalf("Hook saw fields changed by another record")
+		}
+	}
+
+	if len(e.fields) != 1 || e.fields["shared"] != true {
+		t.Errorf("Hooks modified the entry's fields: %v", e.fields)
+	}
+}
+
+func TestHookNilFields(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetTimestampMode(TimestampSequence)
+	l.AddHook(HookFunc(func(rec *Record) error {
+		rec.Fields["host"] = "web1"
+		return nil
+	}))
+
+	l.Info("plain")
+	if out.String() != "1 INFO plain host=web1\n" {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
+
+func TestRecordSetField(t *testing.T) {
+	rec := &Record{}
+	rec.DeleteField("a")
+	rec.SetField("a", 1)
+	if len(rec.Fields) != 1 || rec.Fields["a"] != 1 {
+		t.Errorf("SetField() didn't set the field: %v", rec.Fields)
+	}
+}
@@ -0,0 +1,167 @@
+package clog
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var htmlColors = [8]string{"black", "red", "green", "olive", "blue", "purple", "teal", "silver"}
+var htmlBrightColors = [8]string{"gray", "#f00", "lime", "#ff0", "#00f", "fuchsia", "aqua", "white"}
+
+type ansiStyle struct {
+	color     string
+	bold      bool
+	underline bool
+}
+
+func (s *ansiStyle) apply(params string) {
+	ps := strings.Split(params, ";")
+	for i := 0; i < len(ps); i++ {
+		n, err := strconv.Atoi(ps[i])
+		switch {
+		case ps[i] == "":
+			*s = ansiStyle{}
+		case err != nil:
+		case n == 0:
+			*s = ansiStyle{}
+		case n == 1:
+			s.bold = true
+		case n == 22:
+			s.bold = false
+		case n == 4:
+			s.underline = true
+		case n == 24:
+			s.underline = false
+		case n >= 30 && n <= 37:
+			s.color = htmlColors[n-30]
+		case n == 38:
+			var skip int
+			s.color, skip = extendedColor(ps[i+1:])
+			i += skip
+		case n == 39:
+			s.color = ""
+		case n >= 90 && n <= 97:
+			s.color = htmlBrightColors[n-90]
+		}
+	}
+}
+
+// extendedColor converts the parameters following a 38 code (5;n for a
+// 256-color palette index, or 2;r;g;b for a 24-bit color) to a CSS color,
+// returning it and the number of parameters used.
+func extendedColor(ps []string) (string, int) {
+	num := func(i int) int {
+		if i >= len(ps) {
+			return -1
+		}
+		n, err := strconv.Atoi(ps[i])
+		if err != nil || n < 0 || n > 255 {
+			return -1
+		}
+		return n
+	}
+
+	switch {
+	case len(ps) >= 2 && ps[0] == "5" && num(1) >= 0:
+		n := num(1)
+		switch {
+		case n < 8:
+			return htmlColors[n], 2
+		case n < 16:
+			return htmlBrightColors[n-8], 2
+		case n < 232:
+			steps := [6]int{0, 95, 135, 175, 215, 255}
+			n -= 16
+			return fmt.Sprintf("#%02x%02x%02x", steps[n/36], steps[n/6%6], steps[n%6]), 2
+		default:
+			g := 8 + (n-232)*10
+			return fmt.Sprintf("#%02x%02x%02x", g, g, g), 2
+		}
+	case len(ps) >= 4 && ps[0] == "2" && num(1) >= 0 && num(2) >= 0 && num(3) >= 0:
+		return fmt.Sprintf("#%02x%02x%02x", num(1), num(2), num(3)), 4
+	default:
+		return "", len(ps)
+	}
+}
+
+func (s ansiStyle) css() string {
+	var parts []string
+	if s.color != "" {
+		parts = append(parts, "color:"+s.color)
+	}
+	if s.bold {
+		parts = append(parts, "font-weight:bold")
+	}
+	if s.underline {
+		parts = append(parts, "text-decoration:underline")
+	}
+	return strings.Join(parts, ";")
+}
+
+// ANSIToHTML converts text with ANSI color codes, such as colored log
+// output, to HTML where the colors are preserved as styled span elements.
+// The text itself is HTML-escaped. Unsupported escape codes are discarded.
+func ANSIToHTML(s string) string {
+	b := strings.Builder{}
+	style := ansiStyle{}
+	open := false
+
+	for len(s) > 0 {
+		idx := strings.Index(s, "\x1b[")
+		if idx < 0 {
+			b.WriteString(html.EscapeString(s))
+			break
+		}
+		b.WriteString(html.EscapeString(s[:idx]))
+		s = s[idx+2:]
+
+		end := strings.IndexFunc(s, func(r rune) bool { return r >= '@' && r <= '~' })
+		if end < 0 {
+			break
+		}
+		params, cmd := s[:end], s[end]
+		s = s[end+1:]
+
+		if cmd != 'm' {
+			continue
+		}
+
+		style.apply(params)
+		if open {
+			b.WriteString("</span>")
+			open = false
+		}
+		if css := style.css(); css != "" {
+			b.WriteString(`<span style="` + css + `">`)
+			open = true
+		}
+	}
+
+	if open {
+		b.WriteString("</span>")
+	}
+
+	return b.String()
+}
+
+type htmlWriter struct {
+	w io.Writer
+}
+
+// NewHTMLWriter returns a writer which converts the colored text written to
+// it to HTML using ANSIToHTML before passing it to w. It can be used as the
+// logger output to produce logs for display in a web page. Each write should
+// contain complete lines, which is the case for log output.
+func NewHTMLWriter(w io.Writer) io.Writer {
+	return &htmlWriter{w: w}
+}
+
+func (h *htmlWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(h.w, ANSIToHTML(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,44 @@
+package clog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestANSIToHTML(t *testing.T) {
+	for in, expected := range map[string]string{
+		"plain <text>":                    "plain &lt;text&gt;",
+		"\x1b[33mwarning\x1b[0m":          `<span style="color:olive">warning</span>`,
+		"\x1b[1;31mpanic\x1b[0m done":     `<span style="color:red;font-weight:bold">panic</span> done`,
+		"\x1b[34mblue \x1b[1mbold\x1b[0m": `<span style="color:blue">blue </span><span style="color:blue;font-weight:bold">bold</span>`,
+		"\x1b[31munterminated":            `<span style="color:red">unterminated</span>`,
+		"\x1b[2Kcleared":                  "cleared",
+		"\x1b[38;5;208morange\x1b[0m":     `<span style="color:#ff8700">orange</span>`,
+		"\x1b[38;5;9mred\x1b[0m":          `<span style="color:#f00">red</span>`,
+		"\x1b[38;5;244mgray\x1b[0m":       `<span style="color:#808080">gray</span>`,
+		"\x1b[4;38;2;1;2;3mrgb\x1b[0m":    `<span style="color:#010203;text-decoration:underline">rgb</span>`,
+		"\x1b[38;5mbad\x1b[0m":            "bad",
+		"\x1b[31;?mstill red\x1b[0m":      `<span style="color:red">still red</span>`,
+	} {
+		if out := ANSIToHTML(in); out != expected {
+			t.Errorf("ANSIToHTML(%q) = %q, expected %q", in, out, expected)
+		}
+	}
+}
+
+func TestHTMLWriter(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, true)
+	SetOutput(NewHTMLWriter(&out))
+	SetClock(fixedClock(time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC)))
+	defer SetClock(nil)
+
+	Log(ERROR, "a < b")
+
+	expected := `<span style="color:red">2014-05-06T07:08:09Z ERROR a &lt; b</span>` + "\n"
+	if out.String() != expected {
+		t.Errorf("Incorrect HTML output: %s", out.String())
+	}
+}
@@ -0,0 +1,190 @@
+package clog
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonSchema describes the key names used by a JSON based format.
+type jsonSchema struct {
+	time   string
+	level  string
+	msg    string
+	extra  string
+	rename map[string]string
+	levels *[PANIC + 1]string
+	origin bool
+}
+
+var defaultSchema = jsonSchema{time: "time", level: "level", msg: "msg"}
+
+// ecsSchema maps entries to Elastic Common Schema field names. Common field
+// names are renamed to their ECS equivalents.
+var ecsSchema = jsonSchema{
+	time:  "@timestamp",
+	level: "log.level",
+	msg:   "message",
+	extra: `"ecs.version":"1.6.0"`,
+	rename: map[string]string{
+		"trace_id":       "trace.id",
+		"span_id":        "span.id",
+		"transaction_id": "transaction.id",
+		"service":        "service.name",
+		"error":          "error.message",
+		"stack":          "error.stack_trace",
+		"stack_trace":    "error.stack_trace",
+		"logger":         "log.logger",
+	},
+	origin: true,
+}
+
+// ecsOrigin splits a caller ("dir/file.go:line", optionally followed by
+// ":function") into the ECS log.origin fields.
+func ecsOrigin(caller interface{}) ([]string, []interface{}, bool) {
+	s, ok := caller.(string)
+	parts := strings.SplitN(s, ":", 3)
+	if !ok || len(parts) < 2 {
+		return nil, nil, false
+	}
+	line, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, nil, false
+	}
+
+	names := []string{"log.origin.file.name", "log.origin.file.line"}
+	values := []interface{}{parts[0], line}
+	if len(parts) == 3 {
+		names, values = append(names, "log.origin.function"), append(values, parts[2])
+	}
+	return names, values, true
+}
+
+// gcpSchema uses the keys recognized by Google Cloud Logging (Cloud Run,
+// GKE, App Engine), with its severity names.
+var gcpSchema = jsonSchema{
+	time:  "time",
+	level: "severity",
+	msg:   "message",
+	rename: map[string]string{
+		"trace_id": "logging.googleapis.com/trace",
+		"span_id":  "logging.googleapis.com/spanId",
+	},
+	levels: &[PANIC + 1]string{"DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL", "ALERT"},
+}
+
+// cloudWatchSchema follows the level and msg conventions used by CloudWatch
+// Logs Insights queries.
+var cloudWatchSchema = jsonSchema{time: "timestamp", level: "level", msg: "msg"}
+
+// jsonValue renders the value as JSON. Errors and fmt.Stringer values
+// which don't marshal themselves are rendered as strings, since they
+// usually have no exported fields. Values which can't be marshaled, or
+// panic while being marshaled, are rendered as strings using safeValue.
+func jsonValue(v interface{}) (s string) {
+	defer func() {
+		if recover() != nil {
+			s = jsonValue(safeValue("%v", v))
+		}
+	}()
+
+	switch val := v.(type) {
+	case json.Marshaler, encoding.TextMarshaler:
+	case error:
+		v = val.Error()
+	case fmt.Stringer:
+		v = val.String()
+	}
+
+	if data, err := marshalJSON(v); err == nil {
+		return data
+	}
+
+	data, _ := marshalJSON(safeValue("%v", v))
+	return data
+}
+
+// marshalJSON is like json.Marshal, but doesn't escape <, > and &, so that
+// messages stay readable when searching the logs.
+func marshalJSON(v interface{}) (string, error) {
+	b := strings.Builder{}
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+func jsonTimestamp(t time.Time, tf timeFormat) string {
+	if tf.layout == "" && (tf.mode == TimestampEpoch || tf.mode == TimestampEpochMillis) {
+		return formatTimestamp(t, tf)
+	}
+	return strconv.Quote(formatTimestamp(t, tf))
+}
+
+// formatJSON renders the message as a JSON object. Fields which would clash
+// with the time, level or message keys are prefixed with "fields.", as are
+// fields which would be renamed to the key of an earlier field.
+func formatJSON(schema jsonSchema, t time.Time, tf timeFormat, level LogLevel, msg string, fields Fields) string {
+	b := strings.Builder{}
+	b.WriteString("{" + jsonValue(schema.time) + ":")
+	b.WriteString(jsonTimestamp(t, tf))
+	b.WriteString("," + jsonValue(schema.level) + ":")
+	if schema.levels != nil {
+		b.WriteString(jsonValue(schema.levels[level-DEBUG]))
+	} else {
+		b.WriteString(jsonValue(levelNames[level-DEBUG]))
+	}
+	b.WriteString("," + jsonValue(schema.msg) + ":")
+	b.WriteString(jsonValue(msg))
+
+	if schema.extra != "" {
+		b.WriteString("," + schema.extra)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	used := map[string]bool{}
+	writeField := func(key, name string, value interface{}) {
+		if name == schema.time || name == schema.level || name == schema.msg {
+			name = "fields." + name
+		} else if used[name] {
+			name = "fields." + key
+		}
+		used[name] = true
+
+		b.WriteByte(',')
+		b.WriteString(jsonValue(name))
+		b.WriteByte(':')
+		b.WriteString(jsonValue(value))
+	}
+
+	for _, k := range keys {
+		if k == "caller" && schema.origin {
+			if names, values, ok := ecsOrigin(fields[k]); ok {
+				for i, name := range names {
+					writeField(k, name, values[i])
+				}
+				continue
+			}
+		}
+
+		name := k
+		if renamed, ok := schema.rename[k]; ok {
+			name = renamed
+		}
+		writeField(k, name, fields[k])
+	}
+
+	b.WriteByte('}')
+	return b.String()
+}
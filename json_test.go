@@ -0,0 +1,155 @@
+package clog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONFormat(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, true)
+	SetOutput(&out)
+	SetFormat(FormatJSON)
+	defer SetFormat(FormatText)
+	SetClock(fixedClock(time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC)))
+	defer SetClock(nil)
+
+	WithFields(Fields{"user": 42, "msg": "clash"}).Warning(`disk "almost" full`)
+
+	expected := `{"time":"2014-05-06T07:08:09Z","level":"WARNING","msg":"disk \"almost\" full",` +
+		`"fields.msg":"clash","user":42}` + "\n"
+	if out.String() != expected {
+		t.Errorf("Incorrect JSON output: %s", out.String())
+	}
+
+	out.Reset()
+	SetTimestampMode(TimestampEpoch)
+	defer SetTimestampMode(TimestampRFC3339)
+	InfoKV("unencodable", "ch", make(chan int))
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &parsed); err != nil {
+		t.Fatalf("Invalid JSON output: %s: %s", err, out.String())
+	}
+
+	if parsed["time"] != float64(1399360089) {
+		t.Errorf("Incorrect epoch timestamp in JSON output: %s", out.String())
+	}
+
+	if _, ok := parsed["ch"].(string); !ok {
+		t.Errorf("Unencodable value not rendered as string: %s", out.String())
+	}
+}
+
+type panickyJSON struct{}
+
+func (panickyJSON) MarshalJSON() ([]byte, error) {
+	panic("boom")
+}
+
+type version struct{ major, minor int }
+
+func (v version) String() string {
+	return fmt.Sprintf("v%d.%d", v.major, v.minor)
+}
+
+func TestJSONValue(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{errors.New("disk full"), `"disk full"`},
+		{version{1, 2}, `"v1.2"`},
+		{net.IPv4(10, 0, 0, 1), `"10.0.0.1"`},
+		{time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC), `"2014-05-06T07:08:09Z"`},
+		{panickyJSON{}, `"{}"`},
+		{42, `42`},
+		{"level ERROR -> DEBUG && <b>", `"level ERROR -> DEBUG && <b>"`},
+	}
+	for _, test := range tests {
+		if s := jsonValue(test.value); s != test.expected {
+			t.Errorf("Expected %s for %#v, got %s", test.expected, test.value, s)
+		}
+	}
+}
+
+func TestECSFormat(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+	SetFormat(FormatECS)
+	defer SetFormat(FormatText)
+	SetClock(fixedClock(time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC)))
+	defer SetClock(nil)
+
+	ErrorKV("request failed", "trace_id", "abc", "service", "api", "stack", "main.go:42", "message", "clash")
+
+	expected := `{"@timestamp":"2014-05-06T07:08:09Z","log.level":"ERROR","message":"request failed",` +
+		`"ecs.version":"1.6.0","fields.message":"clash","service.name":"api","error.stack_trace":"main.go:42",` +
+		`"trace.id":"abc"}` + "\n"
+	if out.String() != expected {
+		t.Errorf("Incorrect ECS output: %s", out.String())
+	}
+
+	out.Reset()
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetFormat(FormatECS)
+	l.SetClock(fixedClock(time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC)))
+	l.SetCaller(true, true)
+	l.Named("db").WithFields(Fields{"stack": "a", "stack_trace": "b"}).Error("failed")
+	_, _, line, _ := runtime.Caller(0)
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &parsed); err != nil {
+		t.Fatalf("Invalid JSON output: %s: %s", err, out.String())
+	}
+	file, _ := parsed["log.origin.file.name"].(string)
+	if bytes.Count(out.Bytes(), []byte(`"error.stack_trace"`)) != 1 ||
+		parsed["error.stack_trace"] != "a" || parsed["fields.stack_trace"] != "b" ||
+		parsed["log.logger"] != "db" || !strings.HasSuffix(file, "/json_test.go") ||
+		parsed["log.origin.file.line"] != float64(line-1) ||
+		parsed["log.origin.function"] != pkgPrefix+"TestECSFormat" {
+		t.Errorf("Incorrect ECS output: %s", out.String())
+	}
+}
+
+func TestPlatformJSONFormats(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+	defer SetFormat(FormatText)
+	SetClock(fixedClock(time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC)))
+	defer SetClock(nil)
+
+	SetFormat(FormatGCP)
+	func() {
+		defer func() { recover() }()
+		PanicKV("the end", "trace_id", "projects/p/traces/abc")
+	}()
+
+	expected := `{"time":"2014-05-06T07:08:09Z","severity":"ALERT","message":"the end",` +
+		`"logging.googleapis.com/trace":"projects/p/traces/abc"}` + "\n"
+	if out.String() != expected {
+		t.Errorf("Incorrect GCP output: %s", out.String())
+	}
+
+	out.Reset()
+	SetFormat(FormatCloudWatch)
+	Info("hello")
+
+	expected = `{"timestamp":"2014-05-06T07:08:09Z","level":"INFO","msg":"hello"}` + "\n"
+	if out.String() != expected {
+		t.Errorf("Incorrect CloudWatch output: %s", out.String())
+	}
+}
@@ -0,0 +1,151 @@
+package clog
+
+import (
+	"errors"
+	"strings"
+)
+
+// layoutPart is a piece of a layout: literal text, or a placeholder for a
+// record value or field, with an optional argument (the time layout for
+// {time}).
+type layoutPart struct {
+	text        string
+	placeholder bool
+	arg         string
+}
+
+// layoutFormatter renders records using a layout string.
+type layoutFormatter struct {
+	parts []layoutPart
+	used  map[string]bool
+	tf    timeFormat
+}
+
+// NewLayoutFormatter returns a formatter rendering each message using the
+// layout string, for example "{time} [{level}] {caller} - {msg}". The
+// placeholders are replaced with:
+//
+//	{time}    the message time, as set with SetTimestampMode(),
+//	          SetTimeFormat() and UseUTC(), or {time:<layout>} for a
+//	          custom time layout as for time.Time.Format()
+//	{level}   the level name
+//	{name}    the logger name, set with Named()
+//	{msg}     the message
+//	{fields}  the fields not used elsewhere in the layout, as key=value pairs
+//	{<key>}   the value of the field <key>, eg. {caller} or {user}
+//
+// Use {{ and }} for literal braces. Missing values are replaced with an
+// empty string. A layout without placeholders is an error, as it's most
+// likely a mistake.
+func NewLayoutFormatter(layout string) (Formatter, error) {
+	f := layoutFormatter{used: map[string]bool{}}
+
+	for layout != "" {
+		idx := strings.IndexAny(layout, "{}")
+		if idx < 0 {
+			f.literal(layout)
+			break
+		}
+		if idx+1 < len(layout) && layout[idx+1] == layout[idx] {
+			f.literal(layout[:idx+1])
+			layout = layout[idx+2:]
+			continue
+		}
+		if layout[idx] == '}' {
+			return nil, errors.New("clog: unexpected } in layout")
+		}
+
+		f.literal(layout[:idx])
+		end := strings.IndexByte(layout[idx:], '}')
+		if end < 0 {
+			return nil, errors.New("clog: unclosed { in layout")
+		}
+
+		name := layout[idx+1 : idx+end]
+		part := layoutPart{placeholder: true, text: name}
+		if name == "" {
+			return nil, errors.New("clog: empty placeholder in layout")
+		}
+		if strings.HasPrefix(name, "time:") {
+			part.text, part.arg = "time", name[len("time:"):]
+		}
+		f.parts = append(f.parts, part)
+		f.used[part.text] = true
+		layout = layout[idx+end+1:]
+	}
+
+	if len(f.used) == 0 {
+		return nil, errors.New("clog: layout has no placeholders")
+	}
+	return f, nil
+}
+
+func (f *layoutFormatter) literal(text string) {
+	if text != "" {
+		f.parts = append(f.parts, layoutPart{text: text})
+	}
+}
+
+func (f layoutFormatter) Format(rec Record) []byte {
+	b := strings.Builder{}
+
+	for _, part := range f.parts {
+		if !part.placeholder {
+			b.WriteString(part.text)
+			continue
+		}
+
+		switch part.text {
+		case "time":
+			if part.arg != "" {
+				b.WriteString(formatTimestamp(rec.Time, timeFormat{layout: part.arg, utc: f.tf.utc}))
+			} else {
+				b.WriteString(formatTimestamp(rec.Time, f.tf))
+			}
+		case "level":
+			b.WriteString(levelName(rec.Level))
+		case "name":
+			b.WriteString(rec.Name)
+		case "msg":
+			b.WriteString(rec.Msg)
+		case "fields":
+			rest := Fields{}
+			for k, v := range rec.Fields {
+				if !f.used[k] {
+					rest[k] = v
+				}
+			}
+			b.WriteString(strings.TrimPrefix(formatFields(rest), " "))
+		default:
+			if v, ok := rec.Fields[part.text]; ok {
+				b.WriteString(safeValue("%v", v))
+			}
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// SetLayout sets the default logger to render messages using the layout
+// string, as described in NewLayoutFormatter(). If the layout is invalid,
+// an error is returned and the format is not changed. If layout is empty,
+// the built-in format is used again.
+func SetLayout(layout string) error {
+	return std.SetLayout(layout)
+}
+
+// SetLayout sets the logger to render messages using the layout string, as
+// described in NewLayoutFormatter().
+func (l *Logger) SetLayout(layout string) error {
+	if layout == "" {
+		l.SetFormatter(nil)
+		return nil
+	}
+
+	f, err := NewLayoutFormatter(layout)
+	if err != nil {
+		return err
+	}
+	l.SetFormatter(f)
+	return nil
+}
@@ -0,0 +1,101 @@
+package clog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLayoutFormatter(t *testing.T) {
+	rec := Record{
+		Name:   "db",
+		Level:  WARNING,
+		Time:   time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC),
+		Msg:    "slow query",
+		Fields: Fields{"caller": "db.go:12", "ms": 780, "table": "users"},
+	}
+
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{"{time} [{level}] {caller} - {msg}", "2014-05-06T07:08:09Z [WARNING] db.go:12 - slow query"},
+		{"{time:15:04:05} {name}: {msg} {fields}", "07:08:09 db: slow query caller=db.go:12 ms=780 table=users"},
+		{"{msg} table={table} {fields}", "slow query table=users caller=db.go:12 ms=780"},
+		{"{{{level}}} {user}|", "{WARNING} |"},
+	}
+	for _, test := range tests {
+		f, err := NewLayoutFormatter(test.layout)
+		if err != nil {
+			t.Errorf("Layout %q: %s", test.layout, err)
+			continue
+		}
+		if line := string(f.Format(rec)); line != test.expected {
+			t.Errorf("Layout %q: expected %q, got %q", test.layout, test.expected, line)
+		}
+	}
+
+	for _, layout := range []string{"{msg", "msg}", "{} {msg}", "json", "{{literal}}"} {
+		if _, err := NewLayoutFormatter(layout); err == nil {
+			t.Errorf("Expected error for layout %q", layout)
+		}
+	}
+}
+
+func TestSetupFromEnvLayout(t *testing.T) {
+	Scoped(t)
+	out := bytes.Buffer{}
+	diag := bytes.Buffer{}
+	SetDiagnosticsOutput(&diag)
+
+	env := map[string]string{"LOG_FORMAT": "[{level}] {msg}"}
+	SetupFromEnvPrefix("", func(key string) string { return env[key] })
+	SetOutput(&out)
+	Info("hello")
+
+	if out.String() != "[INFO] hello\n" {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+
+	env["LOG_FORMAT"] = "{msg"
+	SetupFromEnvPrefix("", func(key string) string { return env[key] })
+	if !strings.Contains(diag.String(), `clog: ignoring LOG_FORMAT setting "{msg": clog: unclosed { in layout`) {
+		t.Errorf("Invalid layout not reported: %s", diag.String())
+	}
+}
+
+func TestSetupFromEnvFormatName(t *testing.T) {
+	Scoped(t)
+	out := bytes.Buffer{}
+
+	env := map[string]string{"LOG_FORMAT": "[{level}] {msg}"}
+	SetupFromEnvPrefix("", func(key string) string { return env[key] })
+	env["LOG_FORMAT"] = "JSON"
+	SetupFromEnvPrefix("", func(key string) string { return env[key] })
+	SetOutput(&out)
+	SetTimestampMode(TimestampSequence)
+	Info("hello")
+
+	if out.String() != `{"time":1,"level":"INFO","msg":"hello"}`+"\n" {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
+
+func TestLayoutTimeSettings(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetClock(fixedClock(time.Date(2014, 5, 6, 7, 8, 9, 0, time.FixedZone("CEST", 2*3600))))
+	l.UseUTC(true)
+	l.SetLayout("{time} {time:15:04} {msg}")
+
+	l.Info("utc")
+	l.SetTimeFormat(RFC3339Millis)
+	l.Info("millis")
+
+	expected := "2014-05-06T05:08:09Z 05:08 utc\n2014-05-06T05:08:09.000Z 05:08 millis\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
@@ -0,0 +1,161 @@
+package clog
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+)
+
+// levelAliases maps level names used by other loggers to the closest level.
+var levelAliases = map[string]LogLevel{
+	"TRACE":    DEBUG,
+	"WARN":     WARNING,
+	"ERR":      ERROR,
+	"CRITICAL": FATAL,
+}
+
+// ParseLevel returns the level with the specified name. The name is case
+// insensitive and surrounding whitespace is ignored. Common aliases (TRACE,
+// WARN, ERR and CRITICAL) and level numbers are also accepted.
+func ParseLevel(name string) (LogLevel, error) {
+	n := strings.ToUpper(strings.TrimSpace(name))
+	for idx, ln := range levelNames {
+		if ln == n {
+			return DEBUG + LogLevel(idx), nil
+		}
+	}
+
+	if level, ok := levelAliases[n]; ok {
+		return level, nil
+	}
+
+	if i, err := strconv.Atoi(n); err == nil && LogLevel(i) >= DEBUG && LogLevel(i) <= PANIC {
+		return LogLevel(i), nil
+	}
+
+	return DEBUG, fmt.Errorf("clog: unknown log level %q", strings.TrimSpace(name))
+}
+
+// String returns the level name, or the level number for unknown levels.
+func (level LogLevel) String() string {
+	return levelName(level)
+}
+
+// MarshalText returns the level name, so levels can be used in JSON and
+// other text based configuration formats.
+func (level LogLevel) MarshalText() ([]byte, error) {
+	if level < DEBUG || level > PANIC {
+		return nil, fmt.Errorf("clog: invalid log level %d", int(level))
+	}
+	return []byte(levelNames[level-DEBUG]), nil
+}
+
+// UnmarshalText parses the level name, as ParseLevel does. Together with
+// MarshalText, this also allows using a level as a command line flag with
+// flag.TextVar().
+func (level *LogLevel) UnmarshalText(text []byte) error {
+	l, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*level = l
+	return nil
+}
+
+// LevelHandler returns a http.Handler for viewing and changing the level of
+// the default logger at runtime. A GET request returns the current level
+// name, and a PUT request with a level name as the body sets the level:
+//
+//	curl -X PUT -d debug http://localhost:8080/debug/loglevel
+func LevelHandler() http.Handler {
+	return std.LevelHandler()
+}
+
+// LevelHandler returns a http.Handler for viewing and changing the level of
+// the logger at runtime.
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPut:
+			body, err := io.ReadAll(io.LimitReader(r.Body, 64))
+			if err != nil {
+				http.Error(w, "can't read request body", http.StatusBadRequest)
+				return
+			}
+
+			level, err := ParseLevel(string(body))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			l.setLevel("LevelHandler", level)
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, levelName(l.Level()))
+	})
+}
+
+// HandleSignals installs a handler which makes the default logger more
+// verbose by one level (eg. from INFO to DEBUG) when the verbose signal is
+// received, and less verbose by one level when the quiet signal is
+// received, for example:
+//
+//	stop := clog.HandleSignals(syscall.SIGUSR1, syscall.SIGUSR2)
+//
+// The level stays between DEBUG and PANIC. The returned function removes
+// the handler.
+func HandleSignals(verbose, quiet os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	signal.Notify(ch, verbose, quiet)
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case sig := <-ch:
+				if sig == verbose {
+					std.adjustLevel(-1)
+				} else {
+					std.adjustLevel(1)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+		<-stopped
+	}
+}
+
+// adjustLevel changes the level by delta, keeping it between DEBUG and
+// PANIC.
+func (l *Logger) adjustLevel(delta LogLevel) {
+	l.mu.Lock()
+	old := l.level
+	l.level = old + delta
+	if l.level < DEBUG {
+		l.level = DEBUG
+	} else if l.level > PANIC {
+		l.level = PANIC
+	}
+	level := l.level
+	l.mu.Unlock()
+
+	l.auditChange("HandleSignals", "level", levelName(old), levelName(level))
+}
@@ -0,0 +1,110 @@
+package clog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLevelHandler(t *testing.T) {
+	l := New(INFO, false)
+	h := l.LevelHandler()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "INFO\n" {
+		t.Errorf("Unexpected GET response: %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/", strings.NewReader("debug\n")))
+	if w.Code != http.StatusOK || w.Body.String() != "DEBUG\n" || l.Level() != DEBUG {
+		t.Errorf("Unexpected PUT response: %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/", strings.NewReader("loud")))
+	if w.Code != http.StatusBadRequest || l.Level() != DEBUG {
+		t.Errorf("Expected bad request for unknown level, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected method not allowed, got %d", w.Code)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, level := range []LogLevel{DEBUG, INFO, WARNING, ERROR, FATAL, PANIC} {
+		parsed, err := ParseLevel(strings.ToLower(level.String()))
+		if err != nil || parsed != level {
+			t.Errorf("ParseLevel(%q) returned %v, %v", level.String(), parsed, err)
+		}
+	}
+
+	aliases := map[string]LogLevel{
+		"trace": DEBUG, "Warn": WARNING, "ERR": ERROR, "critical": FATAL, " 2 ": WARNING, "5": PANIC,
+	}
+	for name, level := range aliases {
+		if parsed, err := ParseLevel(name); err != nil || parsed != level {
+			t.Errorf("ParseLevel(%q) returned %v, %v", name, parsed, err)
+		}
+	}
+
+	for _, name := range []string{"6", "-1", ""} {
+		if _, err := ParseLevel(name); err == nil {
+			t.Errorf("Expected error for level %q", name)
+		}
+	}
+
+	if _, err := ParseLevel("loud"); err == nil || err.Error() != `clog: unknown log level "loud"` {
+		t.Errorf("Unexpected error for unknown level: %v", err)
+	}
+
+	if LogLevel(42).String() != "42" {
+		t.Errorf("Unexpected name for unknown level: %s", LogLevel(42))
+	}
+}
+
+func TestLevelText(t *testing.T) {
+	config := struct {
+		Level LogLevel `json:"level"`
+	}{WARNING}
+
+	data, err := json.Marshal(config)
+	if err != nil || string(data) != `{"level":"WARNING"}` {
+		t.Errorf("Unexpected JSON: %s, %v", data, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"level":"error"}`), &config); err != nil || config.Level != ERROR {
+		t.Errorf("Level not unmarshaled: %v, %v", config.Level, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"level":"loud"}`), &config); err == nil {
+		t.Errorf("Expected error unmarshaling unknown level")
+	}
+
+	if _, err := LogLevel(42).MarshalText(); err == nil {
+		t.Errorf("Expected error marshaling invalid level")
+	}
+}
+
+func TestSetupFromEnvUnknownLevel(t *testing.T) {
+	Scoped(t)
+	diag := bytes.Buffer{}
+	SetDiagnosticsOutput(&diag)
+
+	env := map[string]string{"LOG_LEVEL": "loud"}
+	SetupFromEnvPrefix("", func(key string) string { return env[key] })
+
+	if std.level != DEBUG {
+		t.Errorf("Unknown level didn't fall back to DEBUG")
+	}
+	if !strings.Contains(diag.String(), `clog: ignoring LOG_LEVEL setting "loud": clog: unknown log level "loud"`) {
+		t.Errorf("Unknown level not reported: %s", diag.String())
+	}
+}
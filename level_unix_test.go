@@ -0,0 +1,35 @@
+//go:build unix
+
+package clog
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestHandleSignals(t *testing.T) {
+	Scoped(t)
+	SetLevel(INFO)
+
+	stop := HandleSignals(syscall.SIGUSR1, syscall.SIGUSR2)
+	defer stop()
+
+	waitLevel := func(expected LogLevel) {
+		deadline := time.Now().Add(5 * time.Second)
+		for Level() != expected && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if Level() != expected {
+			t.Fatalf("Expected level %s, got %s", levelName(expected), levelName(Level()))
+		}
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitLevel(DEBUG)
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	time.Sleep(10 * time.Millisecond)
+	waitLevel(DEBUG)
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	waitLevel(INFO)
+}
@@ -0,0 +1,28 @@
+package clog
+
+import (
+	"time"
+)
+
+// formatLogfmt renders the message in logfmt format. Fields which would
+// clash with the time, level or msg keys are prefixed with "fields.".
+func formatLogfmt(t time.Time, tf timeFormat, level LogLevel, msg string, fields Fields) string {
+	renamed := fields
+	copied := false
+
+	for _, k := range []string{"time", "level", "msg"} {
+		if v, ok := fields[k]; ok {
+			if !copied {
+				renamed = mergeFields(nil, fields)
+				copied = true
+			}
+			delete(renamed, k)
+			renamed["fields."+k] = v
+		}
+	}
+
+	return "time=" + quoteIfNeeded(formatTimestamp(t, tf)) +
+		" level=" + levelNames[level-DEBUG] +
+		" msg=" + quoteIfNeeded(msg) +
+		formatFields(renamed)
+}
@@ -0,0 +1,25 @@
+package clog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLogfmtFormat(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, true)
+	SetOutput(&out)
+	SetFormat(FormatLogfmt)
+	defer SetFormat(FormatText)
+	SetClock(fixedClock(time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC)))
+	defer SetClock(nil)
+
+	WarningKV("disk almost full", "free", 123, "level", "x", "path", `C:\data`)
+
+	expected := `time=2014-05-06T07:08:09Z level=WARNING msg="disk almost full" fields.level=x free=123 path="C:\\data"` + "\n"
+	if out.String() != expected {
+		t.Errorf("Incorrect logfmt output: %s", out.String())
+	}
+}
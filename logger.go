@@ -0,0 +1,468 @@
+package clog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Logger is a logger instance with its own level, color, output and other
+// settings. The package-level functions use a default Logger, configured
+// with Setup() or SetupFromEnv(). A Logger is safe for concurrent use by
+// multiple goroutines. The zero Logger logs messages of all levels without
+// color, to os.Stderr until an output is set.
+type Logger struct {
+	mu sync.Mutex
+
+	level     LogLevel
+	useColor  bool
+	colorAuto bool
+	ttyFile   *os.File
+	tty       bool
+
+	colors         *[PANIC - DEBUG + 1]string
+	colorLevelOnly bool
+
+	output    io.Writer
+	file      *rotatingFile
+	syslog    *syslogWriter
+	levelOut  [PANIC - DEBUG + 1]io.Writer
+	mustSetup bool
+	warnedNil bool
+	timestamp TimestampMode
+	seq       int64
+	layout    string
+	utc       bool
+	clock     Clock
+	crashDir  string
+	diag      io.Writer
+
+	strictFormat bool
+	audit        bool
+	mirror       mirror
+	format       Format
+
+	caller     bool
+	callerFunc bool
+	callerSkip int
+
+	volume      *volumeStats
+	hooks       []Hook
+	modules     map[string]LogLevel
+	cardinality map[string]*cardinality
+	formatter   Formatter
+}
+
+var std = &Logger{clock: realClock{}}
+
+// New creates a logger using provided level and color settings. The output
+// goes to os.Stderr until changed with SetOutput().
+func New(level LogLevel, useColor bool) *Logger {
+	return &Logger{
+		level:    level,
+		useColor: useColor,
+		output:   os.Stderr,
+		clock:    realClock{},
+	}
+}
+
+// Setup sets up the logger using provided level and color settings, and
+// resets the output to os.Stderr, clearing any per-level outputs.
+func (l *Logger) Setup(level LogLevel, useColor bool) {
+	l.setup("Setup", level, useColor)
+}
+
+func (l *Logger) setup(source string, level LogLevel, useColor bool) {
+	l.mu.Lock()
+	oldLevel, oldColor := l.level, l.useColor
+	l.level = level
+	l.useColor = useColor
+	l.colorAuto = false
+	l.output = os.Stderr
+	l.levelOut = [PANIC - DEBUG + 1]io.Writer{}
+	sw := l.releaseSyslog(nil)
+	l.mu.Unlock()
+
+	if sw != nil {
+		sw.Close()
+	}
+
+	l.auditChange(source, "level", levelName(oldLevel), levelName(level))
+	l.auditChange(source, "color", strconv.FormatBool(oldColor), strconv.FormatBool(useColor))
+}
+
+// MustSetup makes logging a message panic if no output was set up, instead
+// of falling back to os.Stderr.
+func (l *Logger) MustSetup() {
+	l.mu.Lock()
+	l.mustSetup = true
+	l.mu.Unlock()
+}
+
+// SetLevel changes the log level without touching other settings.
+func (l *Logger) SetLevel(level LogLevel) {
+	l.setLevel("SetLevel", level)
+}
+
+func (l *Logger) setLevel(source string, level LogLevel) {
+	l.mu.Lock()
+	old := l.level
+	l.level = level
+	l.mu.Unlock()
+
+	l.auditChange(source, "level", levelName(old), levelName(level))
+}
+
+// Level returns the current log level.
+func (l *Logger) Level() LogLevel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level
+}
+
+// SetOutput sets the output of the logger to go to the specified writer. If
+// output is nil, messages go to os.Stderr.
+func (l *Logger) SetOutput(output io.Writer) {
+	l.mu.Lock()
+	old := l.output
+	l.output = output
+	sw := l.releaseSyslog(output)
+	l.mu.Unlock()
+
+	if sw != nil {
+		sw.Close()
+	}
+
+	l.auditChange("SetOutput", "output", fmt.Sprintf("%T", old), fmt.Sprintf("%T", output))
+}
+
+// SetLevelOutput sets the output for messages with the specified level,
+// overriding the one set with SetOutput(). If output is nil, messages with
+// that level go to the common output again.
+func (l *Logger) SetLevelOutput(level LogLevel, output io.Writer) {
+	if level < DEBUG || level > PANIC {
+		return
+	}
+
+	l.mu.Lock()
+	old := l.levelOut[level-DEBUG]
+	l.levelOut[level-DEBUG] = output
+	l.mu.Unlock()
+
+	l.auditChange("SetLevelOutput("+levelName(level)+")", "output", fmt.Sprintf("%T", old), fmt.Sprintf("%T", output))
+}
+
+// SetTimestampMode sets the format of the timestamp shown with each message.
+func (l *Logger) SetTimestampMode(mode TimestampMode) {
+	l.mu.Lock()
+	old := l.timestamp
+	l.timestamp = mode
+	l.seq = 0
+	l.mu.Unlock()
+
+	l.auditChange("SetTimestampMode", "timestamp", strconv.Itoa(int(old)), strconv.Itoa(int(mode)))
+}
+
+// SetTimeFormat sets the layout used for message timestamps, as for
+// time.Time.Format(), instead of the timestamp mode. If layout is empty, the
+// timestamp mode is used again.
+func (l *Logger) SetTimeFormat(layout string) {
+	l.mu.Lock()
+	old := l.layout
+	l.layout = layout
+	l.mu.Unlock()
+
+	l.auditChange("SetTimeFormat", "time format", strconv.Quote(old), strconv.Quote(layout))
+}
+
+// UseUTC sets whether timestamps are shown in UTC instead of the local
+// time zone.
+func (l *Logger) UseUTC(utc bool) {
+	l.mu.Lock()
+	old := l.utc
+	l.utc = utc
+	l.mu.Unlock()
+
+	l.auditChange("UseUTC", "utc", strconv.FormatBool(old), strconv.FormatBool(utc))
+}
+
+// SetClock sets the clock used for message timestamps. If clock is nil,
+// the real time is used.
+func (l *Logger) SetClock(clock Clock) {
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	l.mu.Lock()
+	l.clock = clock
+	l.mu.Unlock()
+}
+
+// clockLocked returns the clock used for message timestamps, falling back
+// to the real time for the zero Logger. It must be called with the lock
+// held.
+func (l *Logger) clockLocked() Clock {
+	if l.clock == nil {
+		return realClock{}
+	}
+	return l.clock
+}
+
+// clampLevel maps invalid levels to valid ones: levels below DEBUG become
+// DEBUG, and levels above PANIC become ERROR, so that an invalid level never
+// exits or panics.
+func clampLevel(level LogLevel) LogLevel {
+	switch {
+	case level < DEBUG:
+		return DEBUG
+	case level > PANIC:
+		return ERROR
+	default:
+		return level
+	}
+}
+
+// Log logs a message with the specified log level. Invalid levels are
+// reported to the diagnostics output, and logged as DEBUG if below DEBUG,
+// or as ERROR if above PANIC. FATAL messages exit the process even if the
+// level is set to PANIC and the message isn't logged.
+func (l *Logger) Log(level LogLevel, msg string) {
+	l.log("", level, msg, nil)
+}
+
+func (l *Logger) log(name string, level LogLevel, msg string, fields Fields) {
+	if level < DEBUG || level > PANIC {
+		l.diagf("invalid log level %d, logging as %s", level, levelName(clampLevel(level)))
+		level = clampLevel(level)
+	}
+
+	l.mu.Lock()
+	if level < l.levelFor(name) {
+		l.mu.Unlock()
+		if level == FATAL {
+			fatalExit()
+		}
+		return
+	}
+
+	now := l.clockLocked().Now()
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	if l.fireHooks(hooks, name, level, now, &msg, &fields) {
+		l.write(name, level, now, msg, fields)
+	}
+
+	switch level {
+	case FATAL:
+		fatalExit()
+	case PANIC:
+		panic(msg)
+	}
+}
+
+func (l *Logger) write(name string, level LogLevel, now time.Time, msg string, fields Fields) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	output := l.output
+	if w := l.levelOut[level-DEBUG]; w != nil {
+		output = w
+	}
+	if output == nil {
+		output = l.noOutput()
+	}
+
+	if len(l.cardinality) > 0 {
+		fields = l.limitCardinality(now, fields)
+	}
+
+	var caller, site string
+	if l.caller || l.volume != nil {
+		var fn string
+		site, fn = findCaller(l.callerSkip)
+		if l.callerFunc {
+			site += ":" + fn
+		}
+	}
+
+	if l.caller {
+		caller = site
+	}
+
+	rec := Record{Name: name, Level: level, Time: now, Msg: msg, Fields: fields}
+	f := l.formatter
+	if f == nil {
+		f = l.builtinFormatter(output, caller, &rec)
+	} else {
+		if caller != "" {
+			rec.Fields = withField(rec.Fields, "caller", caller)
+		} else {
+			rec.Fields = mergeFields(nil, rec.Fields)
+		}
+		if lf, ok := f.(layoutFormatter); ok {
+			lf.tf = l.recordTime(&rec)
+			f = lf
+		}
+	}
+	line := string(f.Format(rec))
+	if sw, ok := output.(*syslogWriter); ok {
+		line = sw.header(name, level, now) + line
+	}
+
+	fmt.Fprintln(output, line)
+	if r, ok := output.(*rotatingFile); ok {
+		if err := r.rotateError(); err != nil {
+			l.diagfLocked("rotating log file failed: %s", err)
+		}
+	}
+	if l.volume != nil {
+		l.volume.entries[level-DEBUG]++
+		l.volume.bytes[level-DEBUG] += len(line) + 1
+		l.volume.sites[site]++
+	}
+	l.mirror.write(output, now, level, line)
+
+	if level >= FATAL && l.crashDir != "" {
+		if _, err := writeCrashReport(l.crashDir, now, level, msg); err != nil {
+			l.diagfLocked("writing crash report failed: %s", err)
+		}
+	}
+}
+
+// releaseSyslog returns the syslog writer set with SetSyslog() if it's
+// being replaced by the output, so it can be closed. It must be called
+// with the lock held.
+func (l *Logger) releaseSyslog(output io.Writer) *syslogWriter {
+	sw := l.syslog
+	if sw == nil || output == io.Writer(sw) {
+		return nil
+	}
+	l.syslog = nil
+	return sw
+}
+
+// noOutput handles logging without an output set, either panicking in the
+// MustSetup() mode or falling back to os.Stderr. It must be called with the
+// lock held.
+func (l *Logger) noOutput() io.Writer {
+	if l.mustSetup {
+		panic("clog: message logged before the logger was set up")
+	}
+
+	if !l.warnedNil {
+		l.warnedNil = true
+		l.diagfLocked("no output set, writing to os.Stderr; call Setup() or SetOutput() first")
+	}
+	return os.Stderr
+}
+
+// timeFormat holds the settings for formatting message timestamps.
+type timeFormat struct {
+	mode   TimestampMode
+	layout string
+	utc    bool
+}
+
+// timeFormat returns the timestamp settings. It must be called with the
+// lock held.
+func (l *Logger) timeFormat() timeFormat {
+	return timeFormat{mode: l.timestamp, layout: l.layout, utc: l.utc}
+}
+
+// isText reports whether the output format is one of the text formats. It
+// must be called with the lock held.
+func (l *Logger) isText() bool {
+	return l.format == FormatText || l.format == FormatColumns
+}
+
+func formatTimestamp(t time.Time, tf timeFormat) string {
+	if tf.utc {
+		t = t.UTC()
+	}
+	if tf.layout != "" {
+		return t.Format(tf.layout)
+	}
+
+	switch tf.mode {
+	case TimestampRFC3339Nano:
+		return t.Format(time.RFC3339Nano)
+	case TimestampEpoch:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimestampEpochMillis:
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
+
+// Logf logs a message with the specified log level. The function takes a
+// format string and arguments and passes it through fmt.Sprintf() to get
+// the message string.
+func (l *Logger) Logf(level LogLevel, f string, args ...interface{}) {
+	msg := l.sprintf(f, args...)
+	l.Log(level, msg)
+}
+
+// Debug is a convenience method equivalent to Log(DEBUG, msg)
+func (l *Logger) Debug(msg string) {
+	l.Log(DEBUG, msg)
+}
+
+// Info is a convenience method equivalent to Log(INFO, msg)
+func (l *Logger) Info(msg string) {
+	l.Log(INFO, msg)
+}
+
+// Warning is a convenience method equivalent to Log(WARNING, msg)
+func (l *Logger) Warning(msg string) {
+	l.Log(WARNING, msg)
+}
+
+// Error is a convenience method equivalent to Log(ERROR, msg)
+func (l *Logger) Error(msg string) {
+	l.Log(ERROR, msg)
+}
+
+// Fatal is a convenience method equivalent to Log(FATAL, msg)
+func (l *Logger) Fatal(msg string) {
+	l.Log(FATAL, msg)
+}
+
+// Panic is a convenience method equivalent to Log(PANIC, msg)
+func (l *Logger) Panic(msg string) {
+	l.Log(PANIC, msg)
+}
+
+// Debugf is a convenience method equivalent to Logf(DEBUG, fmt, args...)
+func (l *Logger) Debugf(f string, args ...interface{}) {
+	l.Logf(DEBUG, f, args...)
+}
+
+// Infof is a convenience method equivalent to Logf(INFO, fmt, args...)
+func (l *Logger) Infof(f string, args ...interface{}) {
+	l.Logf(INFO, f, args...)
+}
+
+// Warningf is a convenience method equivalent to Logf(WARNING, fmt, args...)
+func (l *Logger) Warningf(f string, args ...interface{}) {
+	l.Logf(WARNING, f, args...)
+}
+
+// Errorf is a convenience method equivalent to Logf(ERROR, fmt, args...)
+func (l *Logger) Errorf(f string, args ...interface{}) {
+	l.Logf(ERROR, f, args...)
+}
+
+// Fatalf is a convenience method equivalent to Logf(FATAL, fmt, args...)
+func (l *Logger) Fatalf(f string, args ...interface{}) {
+	l.Logf(FATAL, f, args...)
+}
+
+// Panicf is a convenience method equivalent to Logf(PANIC, fmt, args...)
+func (l *Logger) Panicf(f string, args ...interface{}) {
+	l.Logf(PANIC, f, args...)
+}
@@ -0,0 +1,205 @@
+package clog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	db := New(DEBUG, false)
+	if db.level != DEBUG || db.useColor != false {
+		t.Errorf("New() doesn't set up logger correctly")
+	}
+
+	dbOut := bytes.Buffer{}
+	db.SetOutput(&dbOut)
+
+	http := New(WARNING, true)
+	httpOut := bytes.Buffer{}
+	http.SetOutput(&httpOut)
+
+	db.Debugf("query took %dms", 42)
+	http.Info("filtered out")
+	http.Warning("slow response")
+
+	if !strings.Contains(dbOut.String(), " DEBUG query took 42ms") {
+		t.Errorf("Message not logged to the logger's output: %s", dbOut.String())
+	}
+
+	if strings.Contains(httpOut.String(), "filtered out") {
+		t.Errorf("Message below the logger's level logged: %s", httpOut.String())
+	}
+
+	if !strings.Contains(httpOut.String(), colorCodes[WARNING-DEBUG]) ||
+		!strings.Contains(httpOut.String(), " WARNING slow response") {
+		t.Errorf("Message not logged correctly: %q", httpOut.String())
+	}
+
+	if strings.Contains(dbOut.String(), "slow response") || strings.Contains(httpOut.String(), "query") {
+		t.Errorf("Loggers don't have separate outputs")
+	}
+}
+
+func TestZeroLogger(t *testing.T) {
+	out := bytes.Buffer{}
+
+	var l Logger
+	l.SetOutput(&out)
+	l.Debug("zero value")
+
+	if !strings.Contains(out.String(), " DEBUG zero value") {
+		t.Errorf("Zero Logger didn't log the message: %q", out.String())
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	l := New(DEBUG, false)
+	outputs := [2]bytes.Buffer{}
+	l.SetOutput(&outputs[0])
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.InfoKV(fmt.Sprintf("message %d/%d", id, j), "worker", id)
+			}
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 100; j++ {
+			l.SetOutput(&outputs[j%2])
+			l.SetTimestampMode(TimestampMode(j % 2))
+		}
+	}()
+
+	wg.Wait()
+
+	lines := strings.SplitAfter(outputs[0].String()+outputs[1].String(), "\n")
+	lines = lines[:len(lines)-1]
+	if len(lines) != 400 {
+		t.Errorf("Expected 400 lines, got %d", len(lines))
+	}
+
+	for _, line := range lines {
+		if strings.Count(line, " INFO message ") != 1 || !strings.Contains(line, " worker=") {
+			t.Errorf("Corrupted line: %q", line)
+			break
+		}
+	}
+}
+
+func TestSetLevelOutput(t *testing.T) {
+	out := bytes.Buffer{}
+	errOut := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	for level := WARNING; level <= PANIC; level++ {
+		l.SetLevelOutput(level, &errOut)
+	}
+
+	l.Info("starting")
+	l.Error("failed")
+	l.Warning("slow")
+
+	if strings.Count(out.String(), "\n") != 1 || !strings.Contains(out.String(), " INFO starting") {
+		t.Errorf("Unexpected common output: %s", out.String())
+	}
+
+	if strings.Count(errOut.String(), "\n") != 2 || !strings.Contains(errOut.String(), " ERROR failed") ||
+		!strings.Contains(errOut.String(), " WARNING slow") {
+		t.Errorf("Unexpected level output: %s", errOut.String())
+	}
+
+	l.SetLevelOutput(ERROR, nil)
+	l.Error("back")
+	if !strings.Contains(out.String(), " ERROR back") {
+		t.Errorf("Level output not reset: %s", out.String())
+	}
+
+	l.Setup(DEBUG, false)
+	if l.levelOut[WARNING-DEBUG] != nil {
+		t.Errorf("Setup didn't clear level outputs")
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(ERROR, false)
+	l.SetOutput(&out)
+
+	l.Info("hidden")
+	l.SetLevel(INFO)
+	l.Info("shown")
+
+	if l.Level() != INFO {
+		t.Errorf("Level() returned %s, expected INFO", levelName(l.Level()))
+	}
+
+	if strings.Contains(out.String(), "hidden") || !strings.Contains(out.String(), " INFO shown") {
+		t.Errorf("Unexpected output after SetLevel(): %s", out.String())
+	}
+}
+
+func TestNoOutput(t *testing.T) {
+	stderr := os.Stderr
+	defer func() { os.Stderr = stderr }()
+	f, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatalf("Can't create file: %s", err)
+	}
+	defer f.Close()
+	os.Stderr = f
+
+	diag := bytes.Buffer{}
+	l := &Logger{clock: realClock{}}
+	l.SetDiagnosticsOutput(&diag)
+	l.Info("early")
+	l.Info("early again")
+
+	data, _ := os.ReadFile(f.Name())
+	if strings.Count(string(data), " INFO early") != 2 {
+		t.Errorf("Messages not written to os.Stderr: %s", data)
+	}
+
+	if strings.Count(diag.String(), "no output set") != 1 {
+		t.Errorf("Expected one diagnostics note, got: %s", diag.String())
+	}
+
+	l.MustSetup()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic when logging before setup")
+		}
+	}()
+	l.Info("too early")
+}
+
+func TestInvalidLevel(t *testing.T) {
+	out := bytes.Buffer{}
+	diag := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetDiagnosticsOutput(&diag)
+
+	l.Log(PANIC+1, "too high")
+	l.Log(DEBUG-1, "too low")
+
+	if !strings.Contains(out.String(), " ERROR too high\n") || !strings.Contains(out.String(), " DEBUG too low\n") {
+		t.Errorf("Invalid levels not clamped: %s", out.String())
+	}
+
+	if !strings.Contains(diag.String(), "invalid log level 6, logging as ERROR") ||
+		!strings.Contains(diag.String(), "invalid log level -1, logging as DEBUG") {
+		t.Errorf("Invalid levels not reported: %s", diag.String())
+	}
+}
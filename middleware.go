@@ -0,0 +1,157 @@
+package clog
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// statusColors are the colors of the status codes in request messages, by
+// class: 1xx and 2xx, 3xx, 4xx and 5xx.
+var statusColors = [...]Color{Green, Green, Cyan, Yellow, Red}
+
+// statusRecorder wraps a http.ResponseWriter, recording the status code
+// and the number of bytes written.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += n
+	return n, err
+}
+
+// Flush sends any buffered data to the client, if the wrapped
+// http.ResponseWriter supports it.
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection, if the wrapped
+// http.ResponseWriter supports it.
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for use with
+// http.ResponseController.
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// HTTPMiddleware returns a http.Handler which calls next, and logs each
+// request with INFO level using the default logger. See
+// HTTPMiddlewareLevel().
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return std.HTTPMiddlewareLevel(INFO, next)
+}
+
+// HTTPMiddlewareLevel returns a http.Handler which calls next, and logs
+// each request with the specified level using the default logger. The
+// message shows the method, path and status code, and the method, path,
+// status, bytes (response size) and duration fields are attached to it.
+// When colors are used, the status code is colored by its class.
+func HTTPMiddlewareLevel(level LogLevel, next http.Handler) http.Handler {
+	return std.HTTPMiddlewareLevel(level, next)
+}
+
+// HTTPMiddleware returns a http.Handler which calls next, and logs each
+// request with INFO level using this logger.
+func (l *Logger) HTTPMiddleware(next http.Handler) http.Handler {
+	return l.HTTPMiddlewareLevel(INFO, next)
+}
+
+// HTTPMiddlewareLevel returns a http.Handler which calls next, and logs
+// each request with the specified level using this logger.
+func (l *Logger) HTTPMiddlewareLevel(level LogLevel, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := l.now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		status := strconv.Itoa(rec.status)
+		if class := rec.status/100 - 1; class >= 0 && class < len(statusColors) {
+			status = l.colorText(level, status, statusColors[class])
+		}
+
+		l.log("", level, r.Method+" "+r.URL.Path+" "+status, Fields{
+			"method":   r.Method,
+			"path":     r.URL.Path,
+			"status":   rec.status,
+			"bytes":    rec.bytes,
+			"duration": l.now().Sub(start),
+		})
+	})
+}
+
+// now returns the current time according to the logger's clock.
+func (l *Logger) now() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clockLocked().Now()
+}
+
+// RecoverMiddleware returns a http.Handler which calls next, recovering
+// panics in it. Panics are logged with ERROR level together with the
+// stack trace using the default logger, and answered with status 500 if
+// nothing was written yet. http.ErrAbortHandler is re-raised, as the
+// http.Server expects.
+func RecoverMiddleware(next http.Handler) http.Handler {
+	return std.RecoverMiddleware(next)
+}
+
+// RecoverMiddleware returns a http.Handler which calls next, logging
+// panics in it using this logger.
+func (l *Logger) RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+
+			l.logPanic(p, false)
+			if rec.status == 0 {
+				http.Error(rec, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+	})
+}
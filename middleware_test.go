@@ -0,0 +1,119 @@
+package clog
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	out := bytes.Buffer{}
+	clock := &manualClock{now: time.Unix(0, 0)}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetClock(clock)
+	l.SetTimestampMode(TimestampSequence)
+
+	h := l.HTTPMiddlewareLevel(DEBUG, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clock.now = clock.now.Add(15 * time.Millisecond)
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/hello?q=secret", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/missing", nil))
+
+	expected := "1 DEBUG GET /hello 200 bytes=5 duration=15ms method=GET path=/hello status=200\n" +
+		"2 DEBUG POST /missing 404 bytes=19 duration=15ms method=POST path=/missing status=404\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (w *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
+
+func TestHTTPMiddlewareInterfaces(t *testing.T) {
+	l := New(DEBUG, false)
+	l.SetOutput(io.Discard)
+
+	h := l.HTTPMiddleware(l.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("event"))
+		w.(http.Flusher).Flush()
+		w.(http.Hijacker).Hijack()
+	})))
+
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/events", nil))
+	if !w.Flushed || !w.hijacked {
+		t.Errorf("Flush or Hijack not passed through (flushed %v, hijacked %v)", w.Flushed, w.hijacked)
+	}
+
+	plain := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := w.(http.Hijacker).Hijack(); err != http.ErrNotSupported {
+			t.Errorf("Expected http.ErrNotSupported, got %v", err)
+		}
+	}))
+	plain.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
+func TestHTTPMiddlewareColor(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, true)
+	l.SetOutput(&out)
+	l.SetTimestampMode(TimestampSequence)
+
+	h := l.HTTPMiddlewareLevel(WARNING, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	status := Red.code() + "500" + colorCodes[WARNING-DEBUG]
+	if !bytes.Contains(out.Bytes(), []byte("GET / "+status+" ")) {
+		t.Errorf("Status not colored: %q", out.String())
+	}
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+
+	h := l.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/abort" {
+			panic(http.ErrAbortHandler)
+		}
+		panic("boom")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+	if !bytes.Contains(out.Bytes(), []byte(" ERROR panic: boom\n")) {
+		t.Errorf("Panic not logged: %s", out.String())
+	}
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("Expected http.ErrAbortHandler to be re-raised, got: %v", p)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/abort", nil))
+}
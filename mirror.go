@@ -0,0 +1,63 @@
+package clog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+type mirror struct {
+	level   LogLevel
+	limit   int
+	output  io.Writer
+	window  time.Time
+	count   int
+	dropped int
+}
+
+// SetStderrMirror mirrors messages with the specified level or higher to
+// os.Stderr, in addition to the configured output. At most limit messages
+// per second are mirrored; the number of messages skipped because of the
+// rate limit is reported with the next mirrored message. A limit of 0
+// disables the mirror. Nothing is mirrored while the output is os.Stderr.
+func SetStderrMirror(level LogLevel, limit int) {
+	std.SetStderrMirror(level, limit)
+}
+
+// SetStderrMirror mirrors messages with the specified level or higher to
+// os.Stderr, at most limit messages per second. A limit of 0 disables the
+// mirror.
+func (l *Logger) SetStderrMirror(level LogLevel, limit int) {
+	l.mu.Lock()
+	l.mirror = mirror{level: level, limit: limit, output: os.Stderr}
+	l.mu.Unlock()
+}
+
+func (m *mirror) write(output io.Writer, now time.Time, level LogLevel, line string) {
+	if m.limit <= 0 || level < m.level {
+		return
+	}
+
+	if f, ok := output.(*os.File); ok && io.Writer(f) == m.output {
+		return
+	}
+
+	if now.Sub(m.window) >= time.Second {
+		m.window = now
+		m.count = 0
+	}
+
+	if m.count >= m.limit {
+		m.dropped++
+		return
+	}
+	m.count++
+
+	if m.dropped > 0 {
+		fmt.Fprintf(m.output, "(%d messages not mirrored due to rate limit)\n", m.dropped)
+		m.dropped = 0
+	}
+
+	fmt.Fprintln(m.output, line)
+}
@@ -0,0 +1,53 @@
+package clog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func TestStderrMirror(t *testing.T) {
+	out := bytes.Buffer{}
+	mirrored := bytes.Buffer{}
+	clock := &manualClock{now: time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC)}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+	SetClock(clock)
+	defer SetClock(nil)
+
+	SetStderrMirror(WARNING, 2)
+	defer SetStderrMirror(DEBUG, 0)
+	std.mirror.output = &mirrored
+
+	Info("not mirrored")
+	Warning("first")
+	Error("second")
+	Error("third")
+	Error("fourth")
+
+	clock.now = clock.now.Add(time.Second)
+	Warning("fifth")
+
+	if strings.Count(out.String(), "\n") != 6 {
+		t.Errorf("Expected all messages in output, got: %s", out.String())
+	}
+
+	expected := `2014-05-06T07:08:09Z WARNING first
+2014-05-06T07:08:09Z ERROR second
+(2 messages not mirrored due to rate limit)
+2014-05-06T07:08:10Z WARNING fifth
+`
+	if mirrored.String() != expected {
+		t.Errorf("Incorrect mirrored output: %s", mirrored.String())
+	}
+}
@@ -0,0 +1,74 @@
+package clog
+
+import (
+	"strings"
+)
+
+// Named returns an entry which logs messages using the default logger, with
+// the name shown in each message. The minimum level for the named messages
+// can be set with SetModuleLevel().
+func Named(name string) *Entry {
+	return std.Named(name)
+}
+
+// Named returns an entry which logs messages using this logger, with the
+// name shown in each message.
+func (l *Logger) Named(name string) *Entry {
+	return &Entry{logger: l, name: name}
+}
+
+// Named returns a new entry with the entry's fields, named by appending the
+// name to the entry's name, separated by a dot.
+func (e *Entry) Named(name string) *Entry {
+	if e.name != "" {
+		name = e.name + "." + name
+	}
+	return &Entry{logger: e.logger, name: name, fields: e.fields}
+}
+
+// SetModuleLevel sets the minimum level for messages logged through named
+// entries with the specified name in the default logger, overriding the
+// logger level. The level also applies to entries with names under it, so
+// "db" applies to "db.pool" unless "db.pool" has its own level.
+func SetModuleLevel(name string, level LogLevel) {
+	std.SetModuleLevel(name, level)
+}
+
+// SetModuleLevel sets the minimum level for messages logged through named
+// entries with the specified name, overriding the logger level.
+func (l *Logger) SetModuleLevel(name string, level LogLevel) {
+	l.setModuleLevel("SetModuleLevel", name, level)
+}
+
+func (l *Logger) setModuleLevel(source, name string, level LogLevel) {
+	l.mu.Lock()
+	old, ok := l.modules[name]
+	if !ok {
+		old = l.level
+	}
+	if l.modules == nil {
+		l.modules = map[string]LogLevel{}
+	}
+	l.modules[name] = level
+	l.mu.Unlock()
+
+	l.auditChange(source, "level of "+name, levelName(old), levelName(level))
+}
+
+// levelFor returns the minimum level for messages with the specified name.
+// It must be called with the lock held.
+func (l *Logger) levelFor(name string) LogLevel {
+	for name != "" && len(l.modules) > 0 {
+		if level, ok := l.modules[name]; ok {
+			return level
+		}
+
+		idx := strings.LastIndexByte(name, '.')
+		if idx < 0 {
+			break
+		}
+		name = name[:idx]
+	}
+
+	return l.level
+}
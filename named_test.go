@@ -0,0 +1,78 @@
+package clog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNamed(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(INFO, false)
+	l.SetOutput(&out)
+	l.SetModuleLevel("db", DEBUG)
+	l.SetModuleLevel("db.pool", WARNING)
+
+	db := l.Named("db")
+	db.Debug("query")
+	db.Named("pool").Info("connection opened")
+	db.Named("pool").Warning("pool exhausted")
+	db.WithFields(Fields{"table": "users"}).Named("migrate").Debug("altered")
+	l.Named("http").Debug("request")
+
+	expected := []string{
+		" DEBUG [db] query",
+		" WARNING [db.pool] pool exhausted",
+		" DEBUG [db.migrate] altered table=users",
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Unexpected output: %s", out.String())
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, expected[i]) {
+			t.Errorf("Expected %q, got %q", expected[i], line)
+		}
+	}
+}
+
+func TestNamedJSON(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetFormat(FormatJSON)
+
+	l.Named("db").Info("query")
+	if !strings.Contains(out.String(), `"logger":"db"`) {
+		t.Errorf("Logger name missing in JSON output: %s", out.String())
+	}
+}
+
+func TestNamedFieldClash(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetFormat(FormatLogfmt)
+	l.SetCaller(true, false)
+
+	l.Named("db").WithFields(Fields{"logger": "pgx", "caller": "pool"}).Info("query")
+	for _, s := range []string{" logger=db", " fields.logger=pgx", " caller=", " fields.caller=pool"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("Missing %q in output: %s", s, out.String())
+		}
+	}
+}
+
+func TestSetupFromEnvModules(t *testing.T) {
+	env := map[string]string{
+		"LOG_LEVEL": "info, envtest=debug,envtest.sub=error",
+	}
+
+	SetupFromEnvPrefix("", func(key string) string { return env[key] })
+	defer func() { std.modules = nil }()
+
+	if std.level != INFO || std.levelFor("envtest") != DEBUG ||
+		std.levelFor("envtest.sub.x") != ERROR || std.levelFor("other") != INFO {
+		t.Errorf("SetupFromEnvPrefix() doesn't set up module levels correctly")
+	}
+}
@@ -0,0 +1,306 @@
+package clog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateOptions specifies when a log file set with SetFile() is rotated,
+// and how many of the old files are kept.
+type RotateOptions struct {
+	// MaxSize is the size in bytes a file may grow to before it's
+	// rotated. If zero, files aren't rotated by size.
+	MaxSize int64
+
+	// MaxAge is how long a file is written to before it's rotated. If
+	// zero, files aren't rotated by age.
+	MaxAge time.Duration
+
+	// MaxBackups is the number of rotated files to keep. If zero, all
+	// rotated files are kept.
+	MaxBackups int
+
+	// Compress enables compressing rotated files with gzip.
+	Compress bool
+}
+
+// backupTime is the layout of the time appended to rotated file names,
+// chosen so the names sort in the order the files were rotated.
+const backupTime = "20060102-150405.000000"
+
+type rotatingFile struct {
+	mu     sync.Mutex
+	path   string
+	opts   RotateOptions
+	clock  Clock
+	f      *os.File
+	size   int64
+	opened time.Time
+	closed bool
+	err    error
+
+	cleanup sync.Mutex
+	pending sync.WaitGroup
+}
+
+// SetFile sets the default logger to write messages to the file at path,
+// appending to it if it exists, and rotating it according to opts. See
+// Logger.SetFile().
+func SetFile(path string, opts RotateOptions) error {
+	return std.SetFile(path, opts)
+}
+
+// SetFile sets the logger to write messages to the file at path, appending
+// to it if it exists. When the file is rotated, it's renamed by appending
+// the time to its name (eg. app.log.20140506-070809.000000), and a new file
+// is created. Compressing and removing old files is done in the
+// background. A file previously set with SetFile() is closed.
+func (l *Logger) SetFile(path string, opts RotateOptions) error {
+	l.mu.Lock()
+	clock := l.clockLocked()
+	l.mu.Unlock()
+
+	r := &rotatingFile{path: path, opts: opts, clock: clock}
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	old := l.file
+	l.file = r
+	l.mu.Unlock()
+
+	l.SetOutput(r)
+	if old != nil {
+		old.Close()
+	}
+	return nil
+}
+
+// open opens the file for appending. It must be called with the lock held,
+// or before the file is in use.
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	r.f, r.size, r.opened = f, info.Size(), r.clock.Now()
+	return nil
+}
+
+// Reopen closes and reopens the file set with SetFile() on the default
+// logger, so that after an external tool like logrotate renames the file,
+// messages go to a new file at the original path. If no file was set, it
+// does nothing.
+func Reopen() error {
+	return std.Reopen()
+}
+
+// Reopen closes and reopens the file set with SetFile() on this logger.
+func (l *Logger) Reopen() error {
+	l.mu.Lock()
+	r := l.file
+	l.mu.Unlock()
+
+	if r == nil {
+		return nil
+	}
+	return r.reopen()
+}
+
+// HandleReopenSignal installs a handler calling Reopen() when one of the
+// signals (usually syscall.SIGHUP) is received, as logrotate expects.
+// Failures to reopen the file are reported to the diagnostics output. The
+// returned function uninstalls the handler.
+func HandleReopenSignal(sigs ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ch:
+				if err := Reopen(); err != nil {
+					std.diagf("reopening log file failed: %s", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+		<-stopped
+	}
+}
+
+func (r *rotatingFile) reopen() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return os.ErrClosed
+	}
+	if r.f != nil {
+		r.f.Close()
+		r.f = nil
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.f == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	bySize := r.opts.MaxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.opts.MaxSize
+	byAge := r.opts.MaxAge > 0 && r.clock.Now().Sub(r.opened) >= r.opts.MaxAge
+	if bySize || byAge {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate renames the current file and opens a new one. If the file can't
+// be renamed (eg. it was moved or removed by something else), a new file
+// is opened anyway, and the error is kept to be reported by the logger.
+// It must be called with the lock held.
+func (r *rotatingFile) rotate() error {
+	r.f.Close()
+	r.f = nil
+
+	backup := r.path + "." + r.clock.Now().Format(backupTime)
+	renameErr := os.Rename(r.path, backup)
+	if renameErr != nil {
+		r.err = renameErr
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return nil
+	}
+
+	r.pending.Add(1)
+	go func() {
+		defer r.pending.Done()
+		r.cleanupBackups(backup)
+	}()
+	return nil
+}
+
+// rotateError returns and clears the error from the last failed rotation.
+func (r *rotatingFile) rotateError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.err
+	r.err = nil
+	return err
+}
+
+// cleanupBackups compresses the newly rotated file if needed, and removes
+// rotated files over the limit.
+func (r *rotatingFile) cleanupBackups(backup string) {
+	r.cleanup.Lock()
+	defer r.cleanup.Unlock()
+
+	if r.opts.Compress {
+		compressFile(backup)
+	}
+	if r.opts.MaxBackups <= 0 {
+		return
+	}
+
+	matches, _ := filepath.Glob(r.path + ".*")
+	var backups []string
+	for _, name := range matches {
+		stamp := strings.TrimSuffix(name[len(r.path)+1:], ".gz")
+		if _, err := time.Parse(backupTime, stamp); err == nil {
+			backups = append(backups, name)
+		}
+	}
+	sort.Strings(backups)
+	for len(backups) > r.opts.MaxBackups {
+		os.Remove(backups[0])
+		backups = backups[1:]
+	}
+}
+
+// compressFile compresses the file with gzip, replacing it with a .gz file.
+// If compressing fails, the file is left as it is.
+func compressFile(name string) {
+	src, err := os.Open(name)
+	if err != nil {
+		return
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return
+	}
+
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if err == nil {
+		err = zw.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		os.Remove(name + ".gz")
+		return
+	}
+	os.Remove(name)
+}
+
+// Close closes the file, waiting for any rotated files to be compressed
+// and cleaned up.
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	var err error
+	if r.f != nil {
+		err = r.f.Close()
+		r.f = nil
+	}
+	r.closed = true
+	r.mu.Unlock()
+
+	r.pending.Wait()
+	return err
+}
@@ -0,0 +1,158 @@
+package clog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readLogFiles(t *testing.T, dir string) map[string]string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{}
+	for _, e := range entries {
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r io.Reader = f
+		if strings.HasSuffix(e.Name(), ".gz") {
+			if r, err = gzip.NewReader(f); err != nil {
+				t.Fatal(err)
+			}
+		}
+		data, _ := io.ReadAll(r)
+		f.Close()
+		files[e.Name()] = string(data)
+	}
+	return files
+}
+
+func TestSetFileRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	clock := &manualClock{now: time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC)}
+	l := New(DEBUG, false)
+	l.SetClock(clock)
+	l.SetTimestampMode(TimestampSequence)
+
+	os.WriteFile(filepath.Join(dir, "app.log.old"), []byte("unrelated\n"), 0644)
+	if err := l.SetFile(filepath.Join(dir, "app.log"), RotateOptions{MaxSize: 30, MaxBackups: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, msg := range []string{"first", "second", "third", "fourth", "fifth", "sixth", "seventh"} {
+		clock.now = clock.now.Add(time.Second)
+		l.Info(msg)
+	}
+	l.file.Close()
+
+	files := readLogFiles(t, dir)
+	expected := map[string]string{
+		"app.log":                        "7 INFO seventh\n",
+		"app.log.20140506-070816.000000": "5 INFO fifth\n6 INFO sixth\n",
+		"app.log.20140506-070814.000000": "3 INFO third\n4 INFO fourth\n",
+		"app.log.old":                    "unrelated\n",
+	}
+	if len(files) != len(expected) {
+		t.Errorf("Unexpected files: %v", files)
+	}
+	for name, data := range expected {
+		if files[name] != data {
+			t.Errorf("Expected %s to contain %q, got %q", name, data, files[name])
+		}
+	}
+}
+
+func TestSetFileRotateByAge(t *testing.T) {
+	dir := t.TempDir()
+	clock := &manualClock{now: time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC)}
+	l := New(DEBUG, false)
+	l.SetClock(clock)
+	l.SetTimestampMode(TimestampSequence)
+
+	if err := l.SetFile(filepath.Join(dir, "app.log"), RotateOptions{MaxAge: time.Hour, Compress: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info("first")
+	clock.now = clock.now.Add(time.Hour)
+	l.Info("second")
+	l.file.Close()
+
+	files := readLogFiles(t, dir)
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) != 2 || names[1] != "app.log.20140506-080809.000000.gz" {
+		t.Fatalf("Unexpected files: %v", names)
+	}
+	if files["app.log"] != "2 INFO second\n" || files[names[1]] != "1 INFO first\n" {
+		t.Errorf("Unexpected contents: %v", files)
+	}
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+	l := New(DEBUG, false)
+	l.SetTimestampMode(TimestampSequence)
+
+	if err := l.Reopen(); err != nil {
+		t.Errorf("Reopen() without a file failed: %s", err)
+	}
+	if err := l.SetFile(name, RotateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	defer l.file.Close()
+
+	l.Info("before")
+	os.Rename(name, name+".1")
+	l.Info("rotated")
+	if err := l.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("after")
+
+	files := readLogFiles(t, dir)
+	if files["app.log.1"] != "1 INFO before\n2 INFO rotated\n" || files["app.log"] != "3 INFO after\n" {
+		t.Errorf("Unexpected files: %v", files)
+	}
+}
+
+func TestSetFileRotateRemoved(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+	diag := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetDiagnosticsOutput(&diag)
+	l.SetTimestampMode(TimestampSequence)
+
+	if err := l.SetFile(name, RotateOptions{MaxSize: 20}); err != nil {
+		t.Fatal(err)
+	}
+	defer l.file.Close()
+
+	l.Info("first")
+	os.Remove(name)
+	l.Info("second")
+
+	files := readLogFiles(t, dir)
+	if len(files) != 1 || files["app.log"] != "2 INFO second\n" {
+		t.Errorf("Unexpected files: %v", files)
+	}
+	if !strings.Contains(diag.String(), "rotating log file failed: ") {
+		t.Errorf("Failed rotation not reported: %s", diag.String())
+	}
+}
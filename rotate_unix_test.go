@@ -0,0 +1,35 @@
+//go:build unix
+
+package clog
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestHandleReopenSignal(t *testing.T) {
+	Scoped(t)
+	name := filepath.Join(t.TempDir(), "app.log")
+	if err := SetFile(name, RotateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	defer std.file.Close()
+
+	stop := HandleReopenSignal(syscall.SIGHUP)
+	defer stop()
+
+	os.Rename(name, name+".1")
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(name); err == nil {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Errorf("File not reopened after SIGHUP")
+}
@@ -0,0 +1,84 @@
+package clog
+
+// Scoped saves the configuration of the default logger and restores it when
+// the test finishes, so tests can call Setup(), SetOutput() and other
+// functions without affecting the tests run after them. It takes a
+// *testing.T, *testing.B or anything else with a Cleanup method:
+//
+//	func TestFoo(t *testing.T) {
+//		clog.Scoped(t)
+//		clog.SetOutput(&buf)
+//		...
+//	}
+//
+// Tests changing the default logger still can't run in parallel with each
+// other.
+func Scoped(t interface{ Cleanup(func()) }) {
+	saved := &Logger{}
+
+	std.mu.Lock()
+	saved.copySettings(std)
+	std.mu.Unlock()
+
+	t.Cleanup(func() {
+		std.mu.Lock()
+		std.copySettings(saved)
+		std.mu.Unlock()
+	})
+}
+
+// copySettings copies all the settings from src to the logger. The caller
+// must hold the locks of both loggers if they're in use.
+func (l *Logger) copySettings(src *Logger) {
+	l.level = src.level
+	l.useColor = src.useColor
+	l.colorAuto = src.colorAuto
+	l.ttyFile = src.ttyFile
+	l.tty = src.tty
+	l.colors = src.colors
+	l.colorLevelOnly = src.colorLevelOnly
+	l.output = src.output
+	l.file = src.file
+	l.syslog = src.syslog
+	l.levelOut = src.levelOut
+	l.mustSetup = src.mustSetup
+	l.warnedNil = src.warnedNil
+	l.timestamp = src.timestamp
+	l.seq = src.seq
+	l.layout = src.layout
+	l.utc = src.utc
+	l.clock = src.clock
+	l.crashDir = src.crashDir
+	l.diag = src.diag
+	l.strictFormat = src.strictFormat
+	l.audit = src.audit
+	l.mirror = src.mirror
+	l.format = src.format
+	l.caller = src.caller
+	l.callerFunc = src.callerFunc
+	l.callerSkip = src.callerSkip
+	l.volume = src.volume
+	l.hooks = src.hooks
+	l.formatter = src.formatter
+
+	l.modules = nil
+	if src.modules != nil {
+		l.modules = make(map[string]LogLevel, len(src.modules))
+		for name, level := range src.modules {
+			l.modules[name] = level
+		}
+	}
+
+	l.cardinality = nil
+	if src.cardinality != nil {
+		l.cardinality = make(map[string]*cardinality, len(src.cardinality))
+		for key, c := range src.cardinality {
+			copied := *c
+			copied.seen = make(map[string]struct{}, len(c.seen))
+			for v := range c.seen {
+				copied.seen[v] = struct{}{}
+			}
+			l.cardinality[key] = &copied
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package clog
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScoped(t *testing.T) {
+	out := bytes.Buffer{}
+	Setup(INFO, false)
+	SetOutput(&out)
+	SetModuleLevel("scopedtest", ERROR)
+
+	t.Run("scoped", func(t *testing.T) {
+		Scoped(t)
+		Setup(DEBUG, true)
+		SetFormat(FormatJSON)
+		SetModuleLevel("scopedtest", DEBUG)
+	})
+
+	if std.level != INFO || std.useColor || std.output != &out || std.format != FormatText ||
+		std.levelFor("scopedtest") != ERROR {
+		t.Errorf("Scoped() didn't restore the configuration")
+	}
+	delete(std.modules, "scopedtest")
+}
+
+func TestScopedCardinality(t *testing.T) {
+	LimitFieldCardinality("scopedtest", 2, time.Minute, CardinalityDrop)
+	defer LimitFieldCardinality("scopedtest", 0, 0, CardinalityDrop)
+
+	t.Run("scoped", func(t *testing.T) {
+		Scoped(t)
+		SetOutput(io.Discard)
+		LimitFieldCardinality("scopedtest", 5, time.Minute, CardinalityHash)
+		InfoKV("seen", "scopedtest", "a")
+	})
+
+	c := std.cardinality["scopedtest"]
+	if c == nil || c.limit != 2 || c.action != CardinalityDrop || len(c.seen) != 0 {
+		t.Errorf("Scoped() didn't restore the cardinality limits: %+v", c)
+	}
+}
+
+func TestCopySettings(t *testing.T) {
+	// Update copySettings when adding settings to Logger.
+	if n := reflect.TypeOf(Logger{}).NumField(); n != 33 {
+		t.Errorf("Logger has %d fields, copySettings() may need updating", n)
+	}
+}
@@ -0,0 +1,106 @@
+//go:build go1.21
+
+package clog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogHandler is a slog.Handler logging records through a Logger.
+type slogHandler struct {
+	logger *Logger
+	fields Fields
+	group  string
+}
+
+// NewSlogHandler returns a slog.Handler which logs records using the
+// default logger, so code using log/slog goes through the same level
+// filtering, colors and output. Attributes become message fields, with
+// group names prepended to their keys (eg. "req.id"). Fields attached to
+// the context with NewContext() are added as well.
+func NewSlogHandler() slog.Handler {
+	return std.NewSlogHandler()
+}
+
+// NewSlogHandler returns a slog.Handler which logs records using this
+// logger.
+func (l *Logger) NewSlogHandler() slog.Handler {
+	return &slogHandler{logger: l}
+}
+
+// slogLevel maps slog levels to the nearest log level at or below them:
+// slog.LevelDebug and below is DEBUG, slog.LevelInfo is INFO,
+// slog.LevelWarn is WARNING and slog.LevelError and above is ERROR. Records
+// never map to FATAL or PANIC, so logging through slog doesn't exit.
+func slogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return DEBUG
+	case level < slog.LevelWarn:
+		return INFO
+	case level < slog.LevelError:
+		return WARNING
+	default:
+		return ERROR
+	}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	h.logger.mu.Lock()
+	defer h.logger.mu.Unlock()
+	return slogLevel(level) >= h.logger.levelFor("")
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := h.fields
+	if ctx != nil && contextFields(ctx) != nil {
+		fields = mergeFields(contextFields(ctx), fields)
+	}
+	if r.NumAttrs() > 0 {
+		fields = mergeFields(fields, nil)
+		r.Attrs(func(a slog.Attr) bool {
+			addSlogAttr(fields, h.group, a)
+			return true
+		})
+	}
+
+	h.logger.log("", slogLevel(r.Level), r.Message, fields)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := mergeFields(h.fields, nil)
+	for _, a := range attrs {
+		addSlogAttr(fields, h.group, a)
+	}
+	return &slogHandler{logger: h.logger, fields: fields, group: h.group}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{logger: h.logger, fields: h.fields, group: h.group + name + "."}
+}
+
+// addSlogAttr adds the attribute to the fields, flattening groups into
+// dot-separated keys. Empty attributes are ignored, as slog.Handler
+// requires.
+func addSlogAttr(fields Fields, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			addSlogAttr(fields, prefix, ga)
+		}
+		return
+	}
+	if a.Key == "" && v.Any() == nil {
+		return
+	}
+
+	fields[prefix+a.Key] = v.Any()
+}
@@ -0,0 +1,67 @@
+//go:build go1.21
+
+package clog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(INFO, false)
+	l.SetOutput(&out)
+	l.SetTimestampMode(TimestampSequence)
+	l.SetCaller(true, false)
+
+	logger := slog.New(l.NewSlogHandler())
+	logger.Debug("hidden")
+	logger.Info("started", "port", 8080)
+	locs := []string{prevLine()}
+	logger.With("req", 1).WithGroup("http").Warn("slow", slog.Group("resp", "status", 200), "ms", 780)
+	locs = append(locs, prevLine())
+	logger.Error("failed", slog.Any("", nil))
+	locs = append(locs, prevLine())
+
+	expected := []struct{ prefix, suffix string }{
+		{"1 INFO ", " started port=8080"},
+		{"2 WARNING ", " slow http.ms=780 http.resp.status=200 req=1"},
+		{"3 ERROR ", " failed"},
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Unexpected output: %q", out.String())
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, expected[i].prefix) || !strings.HasSuffix(line, locs[i]+expected[i].suffix) {
+			t.Errorf("Unexpected line, expected caller %s: %q", locs[i], line)
+		}
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	l := New(WARNING, false)
+	h := l.NewSlogHandler()
+	ctx := context.Background()
+
+	if h.Enabled(ctx, slog.LevelInfo) || !h.Enabled(ctx, slog.LevelWarn) || !h.Enabled(ctx, slog.LevelError+4) {
+		t.Errorf("Enabled() doesn't follow the logger level")
+	}
+}
+
+func TestSlogHandlerContext(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetTimestampMode(TimestampSequence)
+
+	ctx := NewContext(context.Background(), Fields{"req": "r1"})
+	slog.New(l.NewSlogHandler()).InfoContext(ctx, "handled", "ms", 3)
+
+	if out.String() != "1 INFO handled ms=3 req=r1\n" {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
@@ -0,0 +1,104 @@
+package clog
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// streamRetry is the minimum time between reconnection attempts.
+const streamRetry = time.Second
+
+type stream struct {
+	mu        sync.Mutex
+	path      string
+	conn      net.Conn
+	lastDial  time.Time
+	closed    bool
+	retryWait time.Duration
+}
+
+// OpenStream returns a writer streaming log messages to a Unix domain
+// socket, for collectors running next to the program (eg. Vector or Fluent
+// Bit sidecars). It's meant to be used with the JSON output:
+//
+//	clog.SetFormat(clog.FormatJSON)
+//	clog.SetOutput(clog.OpenStream("/run/logs/app.sock"))
+//
+// Each message is sent as a frame: the length of the message as a 4-byte
+// big-endian unsigned integer, followed by the message itself, without the
+// trailing newline. Nothing is sent back by the collector.
+//
+// Writes wait while the collector is slow to read, so the program is
+// slowed down instead of messages being lost. If the collector isn't
+// running or the connection breaks, messages are dropped and the
+// connection is retried at most once a second. A minimal collector is in
+// testdata/streamconsumer.
+func OpenStream(path string) io.WriteCloser {
+	s := &stream{path: path, retryWait: streamRetry}
+	s.dial()
+	return s
+}
+
+// dial connects to the socket. It must be called with the lock held.
+func (s *stream) dial() {
+	s.lastDial = time.Now()
+	conn, err := net.Dial("unix", s.path)
+	if err == nil {
+		s.conn = conn
+	}
+}
+
+func (s *stream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, net.ErrClosed
+	}
+
+	msg := p
+	if n := len(msg); n > 0 && msg[n-1] == '\n' {
+		msg = msg[:n-1]
+	}
+	frame := make([]byte, 4+len(msg))
+	binary.BigEndian.PutUint32(frame, uint32(len(msg)))
+	copy(frame[4:], msg)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if time.Since(s.lastDial) < s.retryWait {
+				break
+			}
+			s.dial()
+			if s.conn == nil {
+				break
+			}
+		}
+
+		if _, err := s.conn.Write(frame); err == nil {
+			return len(p), nil
+		}
+		s.conn.Close()
+		s.conn = nil
+		s.lastDial = time.Time{}
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection.
+func (s *stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,59 @@
+package clog
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func readFrame(t *testing.T, r *bufio.Reader) string {
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		t.Fatalf("Can't read frame length: %s", err)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		t.Fatalf("Can't read frame: %s", err)
+	}
+	return string(msg)
+}
+
+func TestOpenStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+
+	w := OpenStream(path)
+	defer w.Close()
+	w.(*stream).retryWait = 0
+
+	l := New(DEBUG, false)
+	l.SetOutput(w)
+	l.SetFormat(FormatJSON)
+	l.SetTimestampMode(TimestampSequence)
+	l.Info("nobody listening")
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("Can't listen on Unix socket: %s", err)
+	}
+	defer ln.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	l.Info("hello")
+	conn := <-accepted
+	defer conn.Close()
+
+	msg := readFrame(t, bufio.NewReader(conn))
+	if msg != `{"time":2,"level":"INFO","msg":"hello"}` {
+		t.Errorf("Unexpected message: %s", msg)
+	}
+}
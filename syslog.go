@@ -0,0 +1,209 @@
+package clog
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syslogFacility is the facility of the messages sent to syslog (user).
+const syslogFacility = 1
+
+// syslogSeverities maps the log levels to syslog severities.
+var syslogSeverities = [...]int{
+	7, // DEBUG: debug
+	6, // INFO: informational
+	4, // WARNING: warning
+	3, // ERROR: error
+	2, // FATAL: critical
+	1, // PANIC: alert
+}
+
+// syslogTimeout is how long writing a message may take before it's
+// dropped and the connection is closed.
+const syslogTimeout = time.Second
+
+// syslogPaths are the usual locations of the local syslog socket.
+var syslogPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// syslogTextFormatter renders the message part of syslog messages in the
+// text format, as the header already has the time, level and logger name.
+type syslogTextFormatter struct {
+	caller string
+}
+
+func (f syslogTextFormatter) Format(rec Record) []byte {
+	text := rec.Msg
+	if f.caller != "" {
+		text = f.caller + " " + text
+	}
+	return []byte(text + formatFields(rec.Fields))
+}
+
+// syslogName returns the name as a syslog header field: at most limit
+// printable ASCII characters, or "-" if empty.
+func syslogName(name string, limit int) string {
+	name = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, name)
+
+	if len(name) > limit {
+		name = name[:limit]
+	}
+	if name == "" {
+		return "-"
+	}
+	return name
+}
+
+type syslogWriter struct {
+	mu        sync.Mutex
+	hostname  string
+	tag       string
+	pid       string
+	network   string
+	addr      string
+	conn      net.Conn
+	lastDial  time.Time
+	retryWait time.Duration
+	timeout   time.Duration
+}
+
+// SetSyslog sets the default logger to send messages to syslog. See
+// Logger.SetSyslog().
+func SetSyslog(network, addr, tag string) error {
+	return std.SetSyslog(network, addr, tag)
+}
+
+// SetSyslog sets the logger to send messages to syslog, with an RFC5424
+// header using the tag as the app name (the program name if empty). If
+// network is empty, messages go to the local syslog daemon (eg. /dev/log);
+// otherwise network and addr are as for net.Dial(), eg. "udp" and
+// "logs:514". Over TCP, messages are framed with their length, as described
+// in RFC6587.
+//
+// Levels map to the syslog severities debug, informational, warning, error,
+// critical and alert, with the user facility. The logger name is sent as
+// the message ID. In the text format, fields follow the message as
+// key=value pairs; other formats and custom formatters are used for the
+// message part as they are. If the connection breaks, or writing a message
+// takes longer than a second, messages are dropped and reconnecting is
+// retried at most once a second. An error is returned if syslog can't be
+// reached when it's set up. The connection is closed when the output is
+// changed again.
+func (l *Logger) SetSyslog(network, addr, tag string) error {
+	hostname, _ := os.Hostname()
+	if tag == "" {
+		tag = os.Args[0][strings.LastIndexAny(os.Args[0], `/\`)+1:]
+	}
+
+	w := &syslogWriter{
+		hostname:  syslogName(hostname, 255),
+		tag:       syslogName(tag, 48),
+		pid:       strconv.Itoa(os.Getpid()),
+		network:   network,
+		addr:      addr,
+		retryWait: streamRetry,
+		timeout:   syslogTimeout,
+	}
+	if err := w.dial(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.syslog = w
+	l.mu.Unlock()
+
+	l.SetOutput(w)
+	return nil
+}
+
+// header returns the RFC5424 header for a message.
+func (w *syslogWriter) header(name string, level LogLevel, t time.Time) string {
+	msgID := "-"
+	if name != "" {
+		msgID = syslogName(name, 32)
+	}
+
+	pri := syslogFacility*8 + syslogSeverities[level-DEBUG]
+	return "<" + strconv.Itoa(pri) + ">1 " + t.Format("2006-01-02T15:04:05.000000Z07:00") + " " +
+		w.hostname + " " + w.tag + " " + w.pid + " " + msgID + " - "
+}
+
+// dial connects to syslog. It must be called with the lock held.
+func (w *syslogWriter) dial() error {
+	w.lastDial = time.Now()
+
+	if w.network != "" {
+		conn, err := net.Dial(w.network, w.addr)
+		if err == nil {
+			w.conn = conn
+		}
+		return err
+	}
+
+	for _, path := range syslogPaths {
+		if conn, err := net.Dial("unixgram", path); err == nil {
+			w.conn = conn
+			return nil
+		}
+	}
+	return errors.New("clog: local syslog socket not found")
+}
+
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	msg := p
+	if n := len(msg); n > 0 && msg[n-1] == '\n' {
+		msg = msg[:n-1]
+	}
+	if strings.HasPrefix(w.network, "tcp") {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			if time.Since(w.lastDial) < w.retryWait || w.dial() != nil {
+				break
+			}
+		}
+
+		w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+		_, err := w.conn.Write(msg)
+		if err == nil {
+			return len(p), nil
+		}
+		w.conn.Close()
+		w.conn = nil
+
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			w.lastDial = time.Now()
+			break
+		}
+		w.lastDial = time.Time{}
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection.
+func (w *syslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,151 @@
+package clog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetSyslogUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Can't listen on UDP: %s", err)
+	}
+	defer pc.Close()
+
+	l := New(DEBUG, false)
+	if err := l.SetSyslog("udp", pc.LocalAddr().String(), "my app"); err != nil {
+		t.Fatal(err)
+	}
+	l.SetClock(fixedClock(time.Date(2014, 5, 6, 7, 8, 9, 123456789, time.UTC)))
+	l.Named("db").WithFields(Fields{"ms": 780}).Warning("slow query")
+	l.Debug("details")
+
+	pid := strconv.Itoa(os.Getpid())
+	expected := []*regexp.Regexp{
+		regexp.MustCompile(`^<12>1 2014-05-06T07:08:09.123456Z \S+ my_app ` + pid + ` db - slow query ms=780$`),
+		regexp.MustCompile(`^<15>1 2014-05-06T07:08:09.123456Z \S+ my_app ` + pid + ` - - details$`),
+	}
+
+	buf := make([]byte, 1024)
+	for _, re := range expected {
+		pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !re.Match(buf[:n]) {
+			t.Errorf("Message %q doesn't match %s", buf[:n], re)
+		}
+	}
+}
+
+func TestSetSyslogTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Can't listen on TCP: %s", err)
+	}
+	defer ln.Close()
+
+	l := New(DEBUG, false)
+	if err := l.SetSyslog("tcp", ln.Addr().String(), "app"); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	l.Error("failed")
+	l.Info("second")
+
+	r := bufio.NewReader(conn)
+	for _, re := range []string{`^<11>1 \S+ \S+ app \d+ - - failed$`, `^<14>1 \S+ \S+ app \d+ - - second$`} {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		size, err := r.ReadString(' ')
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, _ := strconv.Atoi(size[:len(size)-1])
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			t.Fatal(err)
+		}
+		if !regexp.MustCompile(re).Match(msg) {
+			t.Errorf("Message %q doesn't match %s", msg, re)
+		}
+	}
+}
+
+func TestSetSyslogStalled(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Can't listen on TCP: %s", err)
+	}
+	defer ln.Close()
+
+	l := New(DEBUG, false)
+	if err := l.SetSyslog("tcp", ln.Addr().String(), "app"); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	l.output.(*syslogWriter).timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	msg := strings.Repeat("x", 64<<10)
+	for i := 0; i < 1000; i++ {
+		l.Info(msg)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Logging blocked on a stalled collector for %s", elapsed)
+	}
+}
+
+func TestSetSyslogFormat(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Can't listen on UDP: %s", err)
+	}
+	defer pc.Close()
+
+	l := New(DEBUG, false)
+	if err := l.SetSyslog("udp", pc.LocalAddr().String(), "app"); err != nil {
+		t.Fatal(err)
+	}
+	w := l.output.(*syslogWriter)
+	l.SetFormat(FormatJSON)
+	l.WithFields(Fields{"id": 1}).Info("started")
+
+	buf := make([]byte, 1024)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	re := regexp.MustCompile(`^<14>1 \S+ \S+ app \d+ - - \{.*"msg":"started".*"id":1.*\}$`)
+	if !re.Match(buf[:n]) {
+		t.Errorf("Message %q doesn't match %s", buf[:n], re)
+	}
+
+	out := strings.Builder{}
+	l.SetFormat(FormatText)
+	l.SetOutput(&out)
+	l.Info("local")
+	if s := out.String(); strings.HasPrefix(s, "<") || !strings.Contains(s, " INFO local") {
+		t.Errorf("Syslog header written to another output: %q", s)
+	}
+	if w.conn != nil {
+		t.Error("Syslog connection not closed when the output changed")
+	}
+}
@@ -0,0 +1,56 @@
+// Command streamconsumer is a minimal collector for clog.OpenStream(). It
+// listens on a Unix domain socket and prints each received message on its
+// own line:
+//
+//	go run ./testdata/streamconsumer /tmp/app.sock
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: streamconsumer <socket>")
+		os.Exit(2)
+	}
+
+	os.Remove(os.Args[1])
+	l, err := net.Listen("unix", os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		go consume(conn)
+	}
+}
+
+func consume(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	for {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return
+		}
+
+		msg := make([]byte, size)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			return
+		}
+		fmt.Printf("%s\n", msg)
+	}
+}
@@ -0,0 +1,51 @@
+package clog
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+type testingT interface {
+	Log(args ...interface{})
+	Cleanup(func())
+}
+
+type testWriter struct {
+	mu   sync.Mutex
+	t    testingT
+	done bool
+}
+
+// TestWriter returns a writer which passes each message to t.Log(), so that
+// log output in tests is shown with the test that produced it, and doesn't
+// corrupt the event stream of "go test -json". Messages written after the
+// test has finished are dropped. Use it together with Scoped():
+//
+//	func TestFoo(t *testing.T) {
+//		clog.Scoped(t)
+//		clog.SetOutput(clog.TestWriter(t))
+//		...
+//	}
+//
+// It takes a *testing.T, *testing.B or anything else with Log and Cleanup
+// methods.
+func TestWriter(t testingT) io.Writer {
+	w := &testWriter{t: t}
+	t.Cleanup(func() {
+		w.mu.Lock()
+		w.done = true
+		w.mu.Unlock()
+	})
+	return w
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.done {
+		w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
@@ -0,0 +1,42 @@
+package clog
+
+import (
+	"fmt"
+	"testing"
+)
+
+type fakeT struct {
+	logs     []string
+	cleanups []func()
+}
+
+func (t *fakeT) Log(args ...interface{}) {
+	t.logs = append(t.logs, fmt.Sprint(args...))
+}
+
+func (t *fakeT) Cleanup(fn func()) {
+	t.cleanups = append(t.cleanups, fn)
+}
+
+func TestTestWriter(t *testing.T) {
+	ft := &fakeT{}
+	l := New(DEBUG, false)
+	l.SetTimestampMode(TimestampSequence)
+	l.SetOutput(TestWriter(ft))
+
+	l.Info("hello")
+	for _, fn := range ft.cleanups {
+		fn()
+	}
+	l.Info("after test")
+
+	if len(ft.logs) != 1 || ft.logs[0] != "1 INFO hello" {
+		t.Errorf("Unexpected test logs: %q", ft.logs)
+	}
+}
+
+func TestTestWriterReal(t *testing.T) {
+	Scoped(t)
+	SetOutput(TestWriter(t))
+	Info("logged through t.Log")
+}
@@ -0,0 +1,109 @@
+package clog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type volumeStats struct {
+	entries [PANIC + 1]int
+	bytes   [PANIC + 1]int
+	sites   map[string]int
+}
+
+func newVolumeStats() *volumeStats {
+	return &volumeStats{sites: map[string]int{}}
+}
+
+// StartVolumeReport starts periodically logging a report of the number of
+// messages and bytes logged since the previous report, per level, together
+// with the call sites that logged the most. The report is logged with the
+// specified level. The returned function stops the reports.
+func StartVolumeReport(interval time.Duration, level LogLevel) (stop func()) {
+	return std.StartVolumeReport(interval, level)
+}
+
+// StartVolumeReport starts periodically logging a report of the volume
+// logged through this logger.
+func (l *Logger) StartVolumeReport(interval time.Duration, level LogLevel) (stop func()) {
+	l.mu.Lock()
+	l.volume = newVolumeStats()
+	l.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ticker.C:
+				l.reportVolume(level)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-stopped
+
+		l.mu.Lock()
+		l.volume = nil
+		l.mu.Unlock()
+	}
+}
+
+// topSites returns up to n call sites with the most messages, as
+// "site (count)" strings.
+func (v *volumeStats) topSites(n int) []string {
+	sites := make([]string, 0, len(v.sites))
+	for site := range v.sites {
+		sites = append(sites, site)
+	}
+
+	sort.Slice(sites, func(i, j int) bool {
+		if v.sites[sites[i]] != v.sites[sites[j]] {
+			return v.sites[sites[i]] > v.sites[sites[j]]
+		}
+		return sites[i] < sites[j]
+	})
+
+	if len(sites) > n {
+		sites = sites[:n]
+	}
+	for idx, site := range sites {
+		sites[idx] = fmt.Sprintf("%s (%d)", site, v.sites[site])
+	}
+
+	return sites
+}
+
+func (l *Logger) reportVolume(level LogLevel) {
+	l.mu.Lock()
+	v := l.volume
+	if v == nil {
+		l.mu.Unlock()
+		return
+	}
+	l.volume = newVolumeStats()
+	l.mu.Unlock()
+
+	total, totalBytes := 0, 0
+	levels := []string{}
+	for lvl := DEBUG; lvl <= PANIC; lvl++ {
+		total += v.entries[lvl-DEBUG]
+		totalBytes += v.bytes[lvl-DEBUG]
+		if v.entries[lvl-DEBUG] > 0 {
+			levels = append(levels, fmt.Sprintf("%s=%d/%dB", levelNames[lvl-DEBUG], v.entries[lvl-DEBUG], v.bytes[lvl-DEBUG]))
+		}
+	}
+
+	l.Logf(level, "volume report: %d messages, %d bytes; levels: %s; top call sites: %s",
+		total, totalBytes, strings.Join(levels, " "), strings.Join(v.topSites(5), ", "))
+}
@@ -0,0 +1,40 @@
+package clog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVolumeReport(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetClock(fixedClock(time.Date(2014, 5, 6, 7, 8, 9, 0, time.UTC)))
+
+	stop := l.StartVolumeReport(time.Hour, INFO)
+	defer stop()
+
+	for i := 0; i < 3; i++ {
+		l.Debug("debug")
+	}
+	l.Error("error")
+
+	out.Reset()
+	l.reportVolume(WARNING)
+
+	if !strings.Contains(out.String(), " WARNING volume report: 4 messages, 132 bytes; levels: DEBUG=3/99B ERROR=1/33B; top call sites: ") {
+		t.Errorf("Incorrect volume report: %s", out.String())
+	}
+
+	if !strings.Contains(out.String(), "/volume_test.go:20 (3), ") {
+		t.Errorf("Incorrect top call sites: %s", out.String())
+	}
+
+	out.Reset()
+	l.reportVolume(WARNING)
+	if !strings.Contains(out.String(), " volume report: 1 messages, ") {
+		t.Errorf("Counters not reset after report: %s", out.String())
+	}
+}
@@ -0,0 +1,93 @@
+package clog
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"sync"
+)
+
+type levelWriter struct {
+	mu     sync.Mutex
+	logger *Logger
+	level  LogLevel
+	buf    []byte
+}
+
+// WriterLevel returns an io.Writer that logs each line written to it as a
+// separate message with the specified log level. Incomplete lines are kept
+// until the terminating newline is written.
+func WriterLevel(level LogLevel) io.Writer {
+	return std.WriterLevel(level)
+}
+
+// WriterLevel returns an io.Writer that logs each line written to it with
+// the specified level, using this logger.
+func (l *Logger) WriterLevel(level LogLevel) io.Writer {
+	return &levelWriter{logger: l, level: level}
+}
+
+// Writer returns an io.Writer that logs each line written to it as a
+// separate message with the specified log level, eg. for the Stdout and
+// Stderr of an exec.Cmd. Closing the writer logs the last line if it
+// wasn't terminated by a newline.
+func Writer(level LogLevel) io.WriteCloser {
+	return std.Writer(level)
+}
+
+// Writer returns an io.WriteCloser that logs each line written to it with
+// the specified level, using this logger. Closing it logs any incomplete
+// last line.
+func (l *Logger) Writer(level LogLevel) io.WriteCloser {
+	return &levelWriter{logger: l, level: level}
+}
+
+// StdLogger returns a standard library *log.Logger which logs each message
+// with the specified level using the default logger, for use with code
+// that only accepts a *log.Logger, such as http.Server.ErrorLog.
+func StdLogger(level LogLevel) *log.Logger {
+	return std.StdLogger(level)
+}
+
+// StdLogger returns a standard library *log.Logger which logs each message
+// with the specified level, using this logger. The timestamp and other
+// decorations are added by clog, so the returned logger has no flags or
+// prefix. Note that its Fatal and Panic methods still exit or panic as in
+// the log package, regardless of the level.
+func (l *Logger) StdLogger(level LogLevel) *log.Logger {
+	return log.New(l.WriterLevel(level), "", 0)
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+
+		line := bytes.TrimSuffix(w.buf[:idx], []byte{'\r'})
+		w.buf = w.buf[idx+1:]
+		w.logger.Log(w.level, string(line))
+	}
+
+	return len(p), nil
+}
+
+// Close logs the incomplete last line, if any. The writer can still be
+// used afterwards.
+func (w *levelWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		line := bytes.TrimSuffix(w.buf, []byte{'\r'})
+		w.buf = nil
+		w.logger.Log(w.level, string(line))
+	}
+	return nil
+}
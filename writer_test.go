@@ -0,0 +1,77 @@
+package clog
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestWriterLevel(t *testing.T) {
+	out := bytes.Buffer{}
+
+	Setup(DEBUG, false)
+	SetOutput(&out)
+
+	w := WriterLevel(ERROR)
+	w.Write([]byte("first line\nsecond "))
+	w.Write([]byte("line\n"))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got: %q", out.String())
+	}
+
+	if !strings.HasSuffix(lines[0], " ERROR first line") {
+		t.Errorf("Incorrect first line: %s", lines[0])
+	}
+
+	if !strings.HasSuffix(lines[1], " ERROR second line") {
+		t.Errorf("Incorrect second line: %s", lines[1])
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetTimestampMode(TimestampSequence)
+	l.SetCaller(true, false)
+
+	logger := l.StdLogger(WARNING)
+	logger.Printf("accept error: %s", "too many open files")
+	first := prevLine()
+	logger.Print("no newline")
+	second := prevLine()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 ||
+		!strings.HasPrefix(lines[0], "1 WARNING ") || !strings.HasSuffix(lines[0], first+" accept error: too many open files") ||
+		!strings.HasPrefix(lines[1], "2 WARNING ") || !strings.HasSuffix(lines[1], second+" no newline") {
+		t.Errorf("Unexpected output, expected callers %s and %s: %q", first, second, out.String())
+	}
+}
+
+func TestWriterClose(t *testing.T) {
+	out := bytes.Buffer{}
+	l := New(DEBUG, false)
+	l.SetOutput(&out)
+	l.SetTimestampMode(TimestampSequence)
+
+	cmd := exec.Command("sh", "-c", "echo building; printf done")
+	w := l.Writer(INFO)
+	cmd.Stdout = w
+	if err := cmd.Run(); err != nil {
+		t.Skipf("Can't run sh: %s", err)
+	}
+
+	if out.String() != "1 INFO building\n" {
+		t.Errorf("Unexpected output before Close(): %q", out.String())
+	}
+
+	w.Close()
+	w.Close()
+	if out.String() != "1 INFO building\n2 INFO done\n" {
+		t.Errorf("Unexpected output after Close(): %q", out.String())
+	}
+}